@@ -0,0 +1,129 @@
+package goobj
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var archiveMagic = []byte("!<arch>\n")
+
+const (
+	archiveHeaderSize = 60
+	// the name of the member which holds the package's export data.
+	packageDefName = "__.PKGDEF"
+)
+
+// Archive represents a go package archive (.a) file, which is the ar archive
+// containing the __.PKGDEF member and one or more go object files.
+type Archive struct {
+	Members []ArchiveMember
+	// PackageDef is the raw content of the __.PKGDEF member.
+	PackageDef []byte
+}
+
+// ArchiveMember represents a file in the archive.
+type ArchiveMember struct {
+	Name string
+	Size int64
+	// the member's content starts at this position of the archive
+	Offset int64
+	// File is the parsed go object file. Nil if the member is not a go object file.
+	// Its DataBlockPosition is relative to Offset.
+	File *File
+}
+
+// IsArchive returns true if the given reader starts with the magic of the ar archive.
+func IsArchive(r io.ReaderAt) bool {
+	buff := make([]byte, len(archiveMagic))
+	if _, err := r.ReadAt(buff, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(buff, archiveMagic)
+}
+
+// ParseArchive parses the ar archive which is the first size bytes of the given reader.
+// The go object files in the archive are parsed too.
+func ParseArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	return readArchive(r, size, true)
+}
+
+// ScanArchive reads the members of the ar archive like ParseArchive, but the go object files are not parsed
+// and the File of each member is nil. Use ArchiveMember.NewScanner to read the symbols of the member.
+func ScanArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	return readArchive(r, size, false)
+}
+
+// NewScanner returns the scanner of the member's go object file. r is the archive given to ScanArchive or ParseArchive.
+func (member ArchiveMember) NewScanner(r io.ReaderAt) (*Scanner, error) {
+	return NewScanner(io.NewSectionReader(r, member.Offset, member.Size))
+}
+
+// readArchive reads the members of the ar archive. The go object files are parsed if parse is true.
+func readArchive(r io.ReaderAt, size int64, parse bool) (*Archive, error) {
+	if !IsArchive(r) {
+		return nil, errors.New("archive magic not found")
+	}
+
+	archive := &Archive{}
+	offset := int64(len(archiveMagic))
+	for offset < size {
+		member, err := readArchiveMember(r, offset)
+		if err != nil {
+			return nil, err
+		}
+		if member.Offset+member.Size > size {
+			return nil, fmt.Errorf("member %s exceeds the archive size", member.Name)
+		}
+
+		content := io.NewSectionReader(r, member.Offset, member.Size)
+		if member.Name == packageDefName {
+			archive.PackageDef = make([]byte, member.Size)
+			if _, err := io.ReadFull(content, archive.PackageDef); err != nil {
+				return nil, err
+			}
+		} else if parse && isGoObject(content) {
+			member.File, err = ParseReaderAt(content, member.Size)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse member %s: %v", member.Name, err)
+			}
+		}
+		archive.Members = append(archive.Members, member)
+
+		// the content is padded to the even size
+		offset = member.Offset + member.Size + member.Size%2
+	}
+	return archive, nil
+}
+
+func readArchiveMember(r io.ReaderAt, offset int64) (ArchiveMember, error) {
+	header := make([]byte, archiveHeaderSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return ArchiveMember{}, fmt.Errorf("failed to read the member header at %#x: %v", offset, err)
+	}
+	if string(header[58:60]) != "`\n" {
+		return ArchiveMember{}, fmt.Errorf("invalid member header at %#x", offset)
+	}
+
+	// GNU ar appends '/' to the name
+	name := strings.TrimSuffix(strings.TrimRight(string(header[0:16]), " "), "/")
+	size, err := strconv.ParseInt(strings.TrimRight(string(header[48:58]), " "), 10, 64)
+	if err != nil || size < 0 {
+		return ArchiveMember{}, fmt.Errorf("invalid member size at %#x: %q", offset, header[48:58])
+	}
+	return ArchiveMember{Name: name, Size: size, Offset: offset + archiveHeaderSize}, nil
+}
+
+// the go object file output by the compiler starts with this text.
+var goObjectPrefix = []byte("go object ")
+
+func isGoObject(r io.ReaderAt) bool {
+	buff := make([]byte, len(goObjectPrefix))
+	if _, err := r.ReadAt(buff, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(buff, goObjectPrefix)
+}
@@ -0,0 +1,184 @@
+package goobj
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+type archiveMemberForTesting struct {
+	name    string
+	content []byte
+}
+
+func archiveForTesting(members []archiveMemberForTesting) []byte {
+	buff := bytes.NewBuffer(archiveMagic)
+	for _, member := range members {
+		fmt.Fprintf(buff, "%-16s%-12d%-6d%-6d%-8o%-10d`\n", member.name, 0, 0, 0, 0644, len(member.content))
+		buff.Write(member.content)
+		if len(member.content)%2 == 1 {
+			buff.WriteByte('\n')
+		}
+	}
+	return buff.Bytes()
+}
+
+func TestParseArchive(t *testing.T) {
+	obj, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	pkgdef := []byte("go object darwin amd64 go1.10 X:framepointer\n")
+	data := archiveForTesting([]archiveMemberForTesting{
+		{name: "__.PKGDEF", content: pkgdef},
+		{name: "_go_.o", content: obj},
+		{name: "asm.o/", content: obj},
+		{name: "other", content: []byte("a")},
+	})
+
+	archive, err := ParseArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if !reflect.DeepEqual(pkgdef, archive.PackageDef) {
+		t.Errorf("invalid package def: %s", archive.PackageDef)
+	}
+	if len(archive.Members) != 4 {
+		t.Fatalf("the number of members should be 4, but %d", len(archive.Members))
+	}
+
+	expectedFile := parseFileForTesting(t, helloworldObjPath)
+	for i, expected := range []struct {
+		name   string
+		size   int64
+		offset int64
+		parsed bool
+	}{
+		{name: "__.PKGDEF", size: int64(len(pkgdef)), offset: 68, parsed: false},
+		{name: "_go_.o", size: int64(len(obj)), offset: 68 + int64(len(pkgdef)) + 1 + 60, parsed: true},
+		{name: "asm.o", size: int64(len(obj)), offset: 68 + int64(len(pkgdef)) + 1 + 60 + int64(len(obj)) + 1 + 60, parsed: true},
+		{name: "other", size: 1, parsed: false},
+	} {
+		actual := archive.Members[i]
+		if actual.Name != expected.name || actual.Size != expected.size {
+			t.Errorf("[%d] invalid member: %+v", i, actual)
+		}
+		if expected.offset != 0 && actual.Offset != expected.offset {
+			t.Errorf("[%d] the offset should be %d, but %d", i, expected.offset, actual.Offset)
+		}
+		if !expected.parsed && actual.File != nil {
+			t.Errorf("[%d] the member should not be parsed", i)
+		}
+		if expected.parsed && !reflect.DeepEqual(expectedFile, actual.File) {
+			t.Errorf("[%d] the parsed file should be same as the object file", i)
+		}
+	}
+}
+
+func TestParseArchive_NotArchive(t *testing.T) {
+	data := []byte("not an archive")
+	if _, err := ParseArchive(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestParseArchive_TruncatedMember(t *testing.T) {
+	data := archiveForTesting([]archiveMemberForTesting{{name: "__.PKGDEF", content: []byte("abcd")}})
+	data = data[:len(data)-1]
+	if _, err := ParseArchive(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	if !IsArchive(bytes.NewReader(archiveForTesting(nil))) {
+		t.Errorf("should be archive")
+	}
+	if IsArchive(bytes.NewReader([]byte("!<arch"))) {
+		t.Errorf("should not be archive")
+	}
+}
+
+func TestScanArchive(t *testing.T) {
+	data := archiveForTesting([]archiveMemberForTesting{
+		{name: "_go_.o", content: readFileForTesting(t, helloworldObjPath)},
+		{name: "indexed.o", content: readFileForTesting(t, helloworldIndexedObjPath)},
+	})
+	archive, err := ScanArchive(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if len(archive.Members) != 2 {
+		t.Fatalf("the number of members should be 2, but %d", len(archive.Members))
+	}
+
+	for i, path := range []string{helloworldObjPath, helloworldIndexedObjPath} {
+		member := archive.Members[i]
+		if member.File != nil {
+			t.Errorf("[%d] the member should not be parsed", i)
+		}
+
+		scanner, err := member.NewScanner(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("[%d] error should be nil, but %v", i, err)
+		}
+		expected := parseFileForTesting(t, path)
+		for j := range expected.Symbols {
+			symbol, err := scanner.Next()
+			if err != nil {
+				t.Fatalf("[%d] error should be nil, but %v", i, err)
+			}
+			content, err := scanner.ReadData(symbol.DataAddr)
+			if err != nil || !bytes.Equal(dataOf(expected, expected.Symbols[j].DataAddr), content) {
+				t.Errorf("[%d] the data of the symbol %d should be same as the parsed one: %v", i, j, err)
+			}
+		}
+		if _, err := scanner.Next(); err != io.EOF {
+			t.Errorf("[%d] error should be io.EOF, but %v", i, err)
+		}
+	}
+}
+
+func TestScanArchive_SectionReader(t *testing.T) {
+	for i, path := range []string{helloworldObjPath, helloworldIndexedObjPath} {
+		data := archiveForTesting([]archiveMemberForTesting{
+			{name: "__.PKGDEF", content: []byte("go object darwin amd64 go1.10 X:framepointer\n")},
+			{name: "_go_.o", content: readFileForTesting(t, path)},
+		})
+		archive, err := ScanArchive(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("[%d] error should be nil, but %v", i, err)
+		}
+		member := archive.Members[1]
+
+		scanner, err := NewScanner(io.NewSectionReader(bytes.NewReader(data), member.Offset, member.Size))
+		if err != nil {
+			t.Fatalf("[%d] error should be nil, but %v", i, err)
+		}
+		expected := parseFileForTesting(t, path)
+		for j := range expected.Symbols {
+			symbol, err := scanner.Next()
+			if err != nil {
+				t.Fatalf("[%d] error should be nil, but %v", i, err)
+			}
+			content, err := scanner.ReadData(symbol.DataAddr)
+			if err != nil || !bytes.Equal(dataOf(expected, expected.Symbols[j].DataAddr), content) {
+				t.Errorf("[%d] the data of the symbol %d should be same as the parsed one: %v", i, j, err)
+			}
+		}
+		if _, err := scanner.Next(); err != io.EOF {
+			t.Errorf("[%d] error should be io.EOF, but %v", i, err)
+		}
+	}
+}
+
+func readFileForTesting(t *testing.T, path string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,159 @@
+package goobj
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// ObjectBuilder builds the go object file programmatically. It is mainly for the tests which
+// need the object files with the specific shapes.
+//
+// The symbols returned by AddSymbol can be modified directly until Build is called.
+// The object file is written in the go19ld format.
+type ObjectBuilder struct {
+	file       File
+	symbols    []*Symbol
+	references map[SymbolReference]int64
+}
+
+// NewObjectBuilder returns the builder of the object file for the given platform and go version.
+func NewObjectBuilder(goos, goarch, goVersion string) *ObjectBuilder {
+	b := &ObjectBuilder{references: make(map[SymbolReference]int64)}
+	b.file.Header = Header{GOOS: goos, GOARCH: goarch, GoVersion: goVersion}
+	b.file.RawHeader = []byte(fmt.Sprintf("%s%s %s %s X:none\n!\n", headerLinePrefix, goos, goarch, goVersion))
+	b.file.SymbolReferences = []SymbolReference{{}}
+	b.file.Format = FormatGo19
+	return b
+}
+
+// AddImport adds the package the object file depends on.
+func (b *ObjectBuilder) AddImport(pkg string) {
+	b.file.Imports = append(b.file.Imports, pkg)
+}
+
+// Reference returns the index of the symbol reference. The reference is added if it does not exist.
+// The empty name refers to the nil symbol.
+func (b *ObjectBuilder) Reference(name string, version int64) int64 {
+	if name == "" {
+		return 0
+	}
+
+	reference := SymbolReference{Name: name, Version: version}
+	if index, ok := b.references[reference]; ok {
+		return index
+	}
+	b.file.SymbolReferences = append(b.file.SymbolReferences, reference)
+	index := int64(len(b.file.SymbolReferences) - 1)
+	b.references[reference] = index
+	return index
+}
+
+// AddSymbol adds the symbol whose content is the given data. The size of the symbol is the length of the data.
+// The STEXT symbol has the empty Func.
+func (b *ObjectBuilder) AddSymbol(name string, kind SymKind, data []byte) *Symbol {
+	symbol := &Symbol{
+		IDIndex:  b.Reference(name, 0),
+		Kind:     kind,
+		Size:     int64(len(data)),
+		DataAddr: b.addData(data),
+	}
+	if kind == STEXT {
+		symbol.Func = &StextFields{}
+	}
+	b.symbols = append(b.symbols, symbol)
+	return symbol
+}
+
+// AddRelocation adds the relocation which refers to the target symbol. IDIndex of the relocation is overwritten.
+func (b *ObjectBuilder) AddRelocation(symbol *Symbol, reloc Relocation, target string) {
+	reloc.IDIndex = b.Reference(target, 0)
+	symbol.Relocations = append(symbol.Relocations, reloc)
+}
+
+// AddLocal adds the local variable to the STEXT symbol.
+func (b *ObjectBuilder) AddLocal(symbol *Symbol, name string, offset, typ int64, gotype string) error {
+	if symbol.Func == nil {
+		return errNotFunction
+	}
+
+	local := Local{AsymIndex: b.Reference(name, 0), Offset: offset, Type: typ, GotypeIndex: b.Reference(gotype, 0)}
+	symbol.Func.Local = append(symbol.Func.Local, local)
+	return nil
+}
+
+// AddFile adds the source file to the file list of the STEXT symbol. The pcfile table refers to the list.
+func (b *ObjectBuilder) AddFile(symbol *Symbol, path string) error {
+	if symbol.Func == nil {
+		return errNotFunction
+	}
+
+	symbol.Func.FileIndex = append(symbol.Func.FileIndex, b.Reference(fileSymbolPrefix+path, 0))
+	return nil
+}
+
+// AddFuncData adds the funcdata symbol to the STEXT symbol.
+func (b *ObjectBuilder) AddFuncData(symbol *Symbol, name string, offset int64) error {
+	if symbol.Func == nil {
+		return errNotFunction
+	}
+
+	symbol.Func.FuncDataIndex = append(symbol.Func.FuncDataIndex, b.Reference(name, 0))
+	symbol.Func.FuncDataOffset = append(symbol.Func.FuncDataOffset, offset)
+	return nil
+}
+
+// SetPCLN sets the pcvalue tables of the STEXT symbol. The tables are not validated,
+// so the malformed table can be set. See EncodePCValue to build the valid table.
+func (b *ObjectBuilder) SetPCLN(symbol *Symbol, pcsp, pcfile, pcline, pcinline []byte, pcdata ...[]byte) error {
+	if symbol.Func == nil {
+		return errNotFunction
+	}
+
+	symbol.Func.PCSP = b.addData(pcsp)
+	symbol.Func.PCFile = b.addData(pcfile)
+	symbol.Func.PCLine = b.addData(pcline)
+	symbol.Func.PCInline = b.addData(pcinline)
+	symbol.Func.PCData = nil
+	for _, table := range pcdata {
+		symbol.Func.PCData = append(symbol.Func.PCData, b.addData(table))
+	}
+	return nil
+}
+
+func (b *ObjectBuilder) addData(data []byte) DataAddr {
+	addr := DataAddr{Size: int64(len(data)), Offset: int64(len(b.file.DataBlock))}
+	b.file.DataBlock = append(b.file.DataBlock, data...)
+	return addr
+}
+
+// Build returns the object file. The data block is laid out in the order the data are added,
+// and it is rearranged when the file is written.
+// The returned file does not share any data with the builder, so the file and the builder can be modified independently.
+func (b *ObjectBuilder) Build() *File {
+	file := b.file
+	file.Imports = append([]string(nil), b.file.Imports...)
+	file.SymbolReferences = append([]SymbolReference(nil), b.file.SymbolReferences...)
+	file.DataBlock = append([]byte(nil), b.file.DataBlock...)
+	file.RawHeader = append([]byte(nil), b.file.RawHeader...)
+	file.Symbols = nil
+	for _, symbol := range b.symbols {
+		file.Symbols = append(file.Symbols, copySymbol(*symbol))
+	}
+	file.setLengths()
+	return &file
+}
+
+// WriteTo writes the object file.
+func (b *ObjectBuilder) WriteTo(w io.Writer) (int64, error) {
+	return b.Build().WriteTo(w)
+}
+
+// Bytes returns the content of the object file.
+func (b *ObjectBuilder) Bytes() ([]byte, error) {
+	buff := &bytes.Buffer{}
+	if _, err := b.WriteTo(buff); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
@@ -0,0 +1,200 @@
+package goobj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObjectBuilder(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddImport("fmt.a")
+	mainFunc := b.AddSymbol(`"".main`, STEXT, []byte{0x90, 0x90, 0xc3})
+	b.AddRelocation(mainFunc, Relocation{Offset: 1, Size: 4, Type: R_CALL}, "fmt.Println")
+	if err := b.AddLocal(mainFunc, `"".x`, -8, 1, "type.int"); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if err := b.AddFile(mainFunc, "/a.go"); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if err := b.AddFuncData(mainFunc, "gclocals·a", 0); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	pcline, err := EncodePCValue([]PCValueRange{{0, 2, 10}, {2, 3, 11}}, 1)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	pcfile, _ := EncodePCValue([]PCValueRange{{0, 3, 0}}, 1)
+	if err := b.SetPCLN(mainFunc, nil, pcfile, pcline, nil); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	data := b.AddSymbol(`"".data`, SNOPTRDATA, []byte{1, 2})
+	data.DupOK = true
+
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file, err := ParseBytes(obj)
+	if err != nil {
+		t.Fatalf("failed to parse the built object: %v", err)
+	}
+
+	if file.Header.GoVersion != "go1.10" || !reflect.DeepEqual([]string{"fmt.a"}, file.Imports) {
+		t.Errorf("invalid header or imports: %+v, %v", file.Header, file.Imports)
+	}
+	if len(file.Symbols) != 2 {
+		t.Fatalf("the number of symbols should be 2, but %d", len(file.Symbols))
+	}
+	reloc := file.Symbols[0].Relocations[0]
+	if file.SymbolReferences[reloc.IDIndex].Name != "fmt.Println" || reloc.Type != R_CALL {
+		t.Errorf("invalid relocation: %+v", reloc)
+	}
+	if len(file.Symbols[0].Func.Local) != 1 || len(file.Symbols[0].Func.FuncDataIndex) != 1 {
+		t.Errorf("invalid func: %+v", file.Symbols[0].Func)
+	}
+	if !file.Symbols[1].DupOK {
+		t.Errorf("DupOK should be true")
+	}
+
+	filename, line, err := file.LineForPC(&file.Symbols[0], 2)
+	if err != nil || filename != "/a.go" || line != 11 {
+		t.Errorf("invalid line: %s:%d, %v", filename, line, err)
+	}
+}
+
+func TestObjectBuilder_ManyRelocations(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	symbol := b.AddSymbol(`"".data`, SDATA, make([]byte, 8))
+	for i := 0; i < 10000; i++ {
+		b.AddRelocation(symbol, Relocation{Offset: 0, Size: 8, Type: R_ADDR}, "target")
+	}
+
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file, err := ParseBytes(obj)
+	if err != nil {
+		t.Fatalf("failed to parse the built object: %v", err)
+	}
+	if len(file.Symbols[0].Relocations) != 10000 || file.Header.NumRelocations != 10000 {
+		t.Errorf("the number of relocations should be 10000, but %d", len(file.Symbols[0].Relocations))
+	}
+}
+
+func TestObjectBuilder_NotFunction(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	symbol := b.AddSymbol(`"".data`, SDATA, nil)
+	if err := b.AddFile(symbol, "/a.go"); err == nil {
+		t.Errorf("error should not be nil")
+	}
+	if err := b.SetPCLN(symbol, nil, nil, nil, nil); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestObjectBuilder_Reference(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	if index := b.Reference("", 0); index != 0 {
+		t.Errorf("the index of the nil symbol should be 0, but %d", index)
+	}
+	first := b.Reference("a", 0)
+	if index := b.Reference("a", 0); index != first {
+		t.Errorf("the index should be %d, but %d", first, index)
+	}
+	if index := b.Reference("a", 1); index == first {
+		t.Errorf("the different version should be the different reference")
+	}
+}
+
+func TestObjectBuilder_DupOKCollision(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	first := b.AddSymbol(`"".data`, SRODATA, []byte{1})
+	first.DupOK = true
+	second := b.AddSymbol(`"".data`, SRODATA, []byte{2})
+	second.DupOK = true
+
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file, err := ParseBytes(obj)
+	if err != nil {
+		t.Fatalf("failed to parse the built object: %v", err)
+	}
+	if len(file.Symbols) != 2 || file.Symbols[0].IDIndex != file.Symbols[1].IDIndex {
+		t.Fatalf("the symbols should share the reference: %+v", file.Symbols)
+	}
+	if file.DataBlock[file.Symbols[1].DataAddr.Offset] != 2 {
+		t.Errorf("the data of the 2nd symbol should be 2, but %d", file.DataBlock[file.Symbols[1].DataAddr.Offset])
+	}
+}
+
+func TestObjectBuilder_MalformedPCData(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	symbol := b.AddSymbol(`"".main`, STEXT, []byte{0xc3})
+	// the pc delta is missing
+	if err := b.SetPCLN(symbol, nil, nil, nil, nil, []byte{0x02}); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file, err := ParseBytes(obj)
+	if err != nil {
+		t.Fatalf("failed to parse the built object: %v", err)
+	}
+	if _, err := file.PCData(&file.Symbols[0], 0); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestObjectBuilder_Build_Independent(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddImport("fmt.a")
+	mainFunc := b.AddSymbol(`"".main`, STEXT, []byte{0x90, 0xc3})
+	b.AddRelocation(mainFunc, Relocation{Offset: 1, Size: 4, Type: R_CALL}, "fmt.Println")
+	if err := b.AddFile(mainFunc, "/src/a.go"); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if err := b.AddLocal(mainFunc, `"".x`, -8, 1, "type.int"); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	file := b.Build()
+	if err := file.RenameSymbol(`"".main`, `"".realMain`); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if _, err := file.RetargetRelocations("fmt.Println", `"".shim`); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if _, err := file.RewritePaths("/src", "/dst"); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file.Imports[0] = "os.a"
+	file.DataBlock[0] = 0xcc
+	file.Symbols[0].Func.Local[0].Offset = -16
+
+	b.AddSymbol(`"".data`, SDATA, []byte{1})
+	second := b.Build()
+	if len(second.Symbols) != 2 {
+		t.Fatalf("the number of symbols should be 2, but %d", len(second.Symbols))
+	}
+	if name := second.SymbolReferences[second.Symbols[0].IDIndex].Name; name != `"".main` {
+		t.Errorf("the symbol should not be renamed, but %s", name)
+	}
+	if name := second.SymbolReferences[second.Symbols[0].Relocations[0].IDIndex].Name; name != "fmt.Println" {
+		t.Errorf("the relocation should not be retargeted, but %s", name)
+	}
+	if name := second.SymbolReferences[second.Symbols[0].Func.FileIndex[0]].Name; name != fileSymbolPrefix+"/src/a.go" {
+		t.Errorf("the path should not be rewritten, but %s", name)
+	}
+	if name := second.SymbolReferences[second.Symbols[1].IDIndex].Name; name != `"".data` {
+		t.Errorf("the added symbol should be \"\".data, but %s", name)
+	}
+	if second.Imports[0] != "fmt.a" || second.DataBlock[0] != 0x90 || second.Symbols[0].Func.Local[0].Offset != -8 {
+		t.Errorf("the file should not be changed: %v, %v, %+v", second.Imports, second.DataBlock, second.Symbols[0].Func.Local)
+	}
+}
@@ -3,13 +3,17 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/ks888/goobj"
 )
 
 const testDataDir = "testdata"
@@ -24,11 +28,19 @@ func TestMain(m *testing.M) {
 
 // Use already compiled program as an input because the object file is a little different by underlying OS and CPU.
 var programList = []struct {
-	name, expected string
+	args     []string
+	expected string
 }{
 	{
-		name: filepath.Join(testDataDir, "helloworld.o"),
-		expected: `The list of defined symbols:
+		args: []string{filepath.Join(testDataDir, "helloworld.o")},
+		expected: `The object file header:
+ GOOS: darwin
+ GOARCH: amd64
+ Go version: go1.10
+ Experiments: framepointer
+The list of imported packages:
+ fmt.a
+The list of defined symbols:
  Offset Size Type        DupOK Local MakeTypeLink Name                                       Version GoType
  0x3db  0x6e STEXT       false false false        "".main                                    0
  0x468  0x5b STEXT       false false false        "".init                                    0
@@ -54,6 +66,33 @@ var programList = []struct {
  0x6f6  0x8  SRODATA     true  false false        gclocals·69c1753bd5f81501d95132d08af04464 0
  0x6fe  0xa  SRODATA     true  false false        gclocals·e226d4ae4a7cad8835311c6a4683c14f 0
  0x708  0x8  SRODATA     true  false false        gclocals·33cdeccccebe80329f1fdbee7f5874cb 0`},
+	{
+		args:     []string{"imports", filepath.Join(testDataDir, "helloworld.o")},
+		expected: "The list of imported packages:\n fmt.a"},
+	{
+		args: []string{"inline", filepath.Join(testDataDir, "helloworld.o")},
+		expected: `The inlining tree of "".main:
+ Index Parent Func File Line
+The inlining tree of "".init:
+ Index Parent Func File Line`},
+	{
+		args: []string{"lines", filepath.Join(testDataDir, "helloworld.o")},
+		expected: `The line table:
+ Func    StartPC EndPC File                                                                             Line
+ "".main 0x0     0x1d  /Users/yagami/go/src/github.com/ks888/goobj/cmd/readgoobj/testdata/helloworld.go 7
+ "".main 0x1d    0x5d  /Users/yagami/go/src/github.com/ks888/goobj/cmd/readgoobj/testdata/helloworld.go 8
+ "".main 0x5d    0x67  /Users/yagami/go/src/github.com/ks888/goobj/cmd/readgoobj/testdata/helloworld.go 9
+ "".main 0x67    0x6e  /Users/yagami/go/src/github.com/ks888/goobj/cmd/readgoobj/testdata/helloworld.go 7
+ "".init 0x0     0x5b  <autogenerated>                                                                  1`},
+	{
+		args: []string{"lines", filepath.Join(testDataDir, "helloworld_indexed.o")},
+		expected: `The line table:
+ Func      StartPC EndPC File                     Line
+ main.main 0x0     0xe   helloworld.go            7
+ main.main 0xe     0x26  helloworld.go            8
+ main.main 0x26    0x45  $GOROOT/src/fmt/print.go 307
+ main.main 0x45    0x4b  helloworld.go            9
+ main.main 0x4b    0x52  helloworld.go            7`},
 }
 
 func TestSamplePrograms(t *testing.T) {
@@ -61,7 +100,7 @@ func TestSamplePrograms(t *testing.T) {
 	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
 
 	for i, program := range programList {
-		out, err := exec.Command(cmdPath, program.name).CombinedOutput()
+		out, err := exec.Command(cmdPath, program.args...).CombinedOutput()
 		if err != nil {
 			t.Fatalf("[%d] failed to run program\nerr: %v\nout: %v", i, err, string(out))
 		}
@@ -75,3 +114,217 @@ func TestSamplePrograms(t *testing.T) {
 		}
 	}
 }
+
+func TestStdin(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	in, err := os.Open(filepath.Join(testDataDir, "helloworld.o"))
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	defer in.Close()
+
+	cmd := exec.Command(cmdPath, "imports", "-")
+	cmd.Stdin = in
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+
+	expected := "The list of imported packages:\n fmt.a\n"
+	if string(out) != expected {
+		t.Errorf("invalid output:\nexpect: %s\nactual: %s", expected, string(out))
+	}
+}
+
+func TestArchive(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	obj, err := ioutil.ReadFile(filepath.Join(testDataDir, "helloworld.o"))
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	archive := bytes.NewBufferString("!<arch>\n")
+	fmt.Fprintf(archive, "%-16s%-12d%-6d%-6d%-8o%-10d`\n", "_go_.o", 0, 0, 0, 0644, len(obj))
+	archive.Write(obj)
+
+	cmd := exec.Command(cmdPath, "imports", "-")
+	cmd.Stdin = archive
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+
+	expected := "Member _go_.o:\nThe list of imported packages:\n fmt.a\n"
+	if string(out) != expected {
+		t.Errorf("invalid output:\nexpect: %s\nactual: %s", expected, string(out))
+	}
+}
+
+func TestStrip(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	stripped, err := exec.Command(cmdPath, "strip", filepath.Join(testDataDir, "helloworld.o")).Output()
+	if err != nil {
+		t.Fatalf("failed to run program: %v", err)
+	}
+
+	cmd := exec.Command(cmdPath, "symbols", "-")
+	cmd.Stdin = bytes.NewReader(stripped)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+
+	if strings.Contains(string(out), "SDWARF") {
+		t.Errorf("the DWARF symbols should be removed:\n%s", string(out))
+	}
+	if !strings.Contains(string(out), `"".main`) {
+		t.Errorf("the other symbols should be kept:\n%s", string(out))
+	}
+}
+
+func TestStrip_Kinds(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	stripped, err := exec.Command(cmdPath, "strip", "--kinds", "SDWARFLOC,SDWARFRANGE", filepath.Join(testDataDir, "helloworld.o")).Output()
+	if err != nil {
+		t.Fatalf("failed to run program: %v", err)
+	}
+
+	cmd := exec.Command(cmdPath, "symbols", "-")
+	cmd.Stdin = bytes.NewReader(stripped)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+
+	if strings.Contains(string(out), "SDWARFLOC") || strings.Contains(string(out), "SDWARFRANGE") {
+		t.Errorf("the symbols of the given kinds should be removed:\n%s", string(out))
+	}
+	if !strings.Contains(string(out), "SDWARFINFO") {
+		t.Errorf("the symbols of the other kinds should be kept:\n%s", string(out))
+	}
+
+	if out, err := exec.Command(cmdPath, "strip", "--kinds", "SUNKNOWN", filepath.Join(testDataDir, "helloworld.o")).CombinedOutput(); err == nil {
+		t.Errorf("the unknown kind should be rejected:\n%s", string(out))
+	}
+}
+
+func TestStrip_IndexedFormat(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	out, err := exec.Command(cmdPath, "strip", filepath.Join(testDataDir, "helloworld_indexed.o")).Output()
+	if err == nil {
+		t.Errorf("the indexed format should be rejected")
+	}
+	if len(out) != 0 {
+		t.Errorf("nothing should be written, but %d bytes", len(out))
+	}
+}
+
+func TestRewritePaths(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	rewritten, err := exec.Command(cmdPath, "rewrite-paths", "--from", "/Users/yagami/go/src/github.com/ks888/goobj", "--to", "",
+		filepath.Join(testDataDir, "helloworld.o")).Output()
+	if err != nil {
+		t.Fatalf("failed to run program: %v", err)
+	}
+
+	cmd := exec.Command(cmdPath, "lines", "-")
+	cmd.Stdin = bytes.NewReader(rewritten)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+
+	if strings.Contains(string(out), "/Users/yagami") || !strings.Contains(string(out), " cmd/readgoobj/testdata/helloworld.go ") {
+		t.Errorf("the paths should be rewritten:\n%s", string(out))
+	}
+
+	for _, from := range []string{"", "/not/found"} {
+		out, err := exec.Command(cmdPath, "rewrite-paths", "--from", from, "--to", "/build", filepath.Join(testDataDir, "helloworld.o")).Output()
+		if err == nil {
+			t.Errorf("the prefix %q should be rejected", from)
+		}
+		if len(out) != 0 {
+			t.Errorf("nothing should be written, but %d bytes", len(out))
+		}
+	}
+}
+
+func TestRename(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	renamed, err := exec.Command(cmdPath, "rename", "--from", `"".main`, "--to", `"".realMain`, filepath.Join(testDataDir, "helloworld.o")).Output()
+	if err != nil {
+		t.Fatalf("failed to run program: %v", err)
+	}
+
+	cmd := exec.Command(cmdPath, "symbols", "-")
+	cmd.Stdin = bytes.NewReader(renamed)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+
+	if !strings.Contains(string(out), `"".realMain`) {
+		t.Errorf("the symbol should be renamed:\n%s", string(out))
+	}
+
+	out, err = exec.Command(cmdPath, "rename", "--from", `"".main`, "--to", `"".init`, filepath.Join(testDataDir, "helloworld.o")).CombinedOutput()
+	if err == nil {
+		t.Errorf("the name collision should be error:\n%s", string(out))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	out, err := exec.Command(cmdPath, "verify", filepath.Join(testDataDir, "helloworld.o")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run program\nerr: %v\nout: %v", err, string(out))
+	}
+	if string(out) != "No problem found\n" {
+		t.Errorf("invalid output: %s", string(out))
+	}
+}
+
+func TestVerify_Archive(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	var cmdPath = filepath.Join(filepath.Dir(filename), "readgoobj")
+
+	archive := bytes.NewBufferString("!<arch>\n")
+	for _, name := range []string{"a.o", "b.o"} {
+		b := goobj.NewObjectBuilder("linux", "amd64", "go1.10")
+		b.AddSymbol(`"".main`, goobj.STEXT, []byte{0xc3}).GoTypeIndex = 100
+		obj, err := b.Bytes()
+		if err != nil {
+			t.Fatalf("failed to build the object file: %v", err)
+		}
+		fmt.Fprintf(archive, "%-16s%-12d%-6d%-6d%-8o%-10d`\n", name, 0, 0, 0, 0644, len(obj))
+		archive.Write(obj)
+		if len(obj)%2 == 1 {
+			archive.WriteByte('\n')
+		}
+	}
+
+	cmd := exec.Command(cmdPath, "verify", "-")
+	cmd.Stdin = archive
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Errorf("the problems should be reported by the exit status")
+	}
+	if !strings.Contains(string(out), "failed to verify goobj file a.o") || !strings.Contains(string(out), "failed to verify goobj file b.o") {
+		t.Errorf("the problems of all the members should be reported:\n%s", string(out))
+	}
+}
@@ -1,31 +1,285 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"os"
+	"strings"
 
 	"github.com/ks888/goobj"
 )
 
+const usage = `Usage: %s [command] [go object file or package archive]
+       %s strip [--kinds [kind,...]] [go object file]
+       %s [rewrite-paths|rename|retarget] --from [name] --to [name] [go object file]
+
+Reads the file from the standard input if the file name is -.
+If the file is the package archive (.a), prints each go object file in the archive.
+
+If the command is omitted, prints the header, the imported packages and the defined symbols.
+
+Commands:
+  header   print the target platform and the toolchain
+  imports  print the imported packages
+  symbols  print the defined symbols
+  inline   print the inlining tree of each function
+  lines    print the source position of each function's instructions
+  verify   check the go object file is consistent. Exits with the non-zero status if any problem is found
+  strip    write the go object file without the symbols of --kinds, like SDWARFINFO,SDWARFLOC, to the standard output.
+           The DWARF symbols are removed if --kinds is omitted. Only the go19ld format is supported
+  rewrite-paths
+           write the go object file whose source file paths starting with --from are rewritten to start with --to
+           to the standard output. The prefix is removed if --to is empty
+  rename   write the go object file whose symbol --from is renamed to --to to the standard output
+  retarget write the go object file whose relocations to --from are changed to refer to --to to the standard output
+`
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Printf("Usage: %s [go object file]\n", os.Args[0])
-		os.Exit(1)
+		printUsage()
 	}
 
-	filename := os.Args[1]
-	f, err := os.Open(filename)
+	command, filename := "", os.Args[1]
+	if len(os.Args) >= 3 {
+		command, filename = os.Args[1], os.Args[2]
+	}
+
+	// what printFunc does, which is used in the error message.
+	action := "print"
+	var printFunc func(*goobj.File) error
+	switch command {
+	case "":
+		printFunc = withoutError(printSummary)
+	case "header":
+		printFunc = withoutError(goobj.PrintHeader)
+	case "imports":
+		printFunc = withoutError(goobj.PrintImports)
+	case "symbols":
+		printFunc = withoutError(goobj.PrintSymbols)
+	case "inline":
+		printFunc = withoutError(goobj.PrintInlinedCalls)
+	case "lines":
+		printFunc = goobj.PrintLines
+	case "verify":
+		action, printFunc = "verify", verify
+	case "strip":
+		var kinds []goobj.SymKind
+		kinds, filename = parseKinds()
+		action, printFunc = "write", strip(kinds)
+	case "rewrite-paths", "rename", "retarget":
+		var from, to string
+		from, to, filename = parseFromTo(command)
+		action, printFunc = "write", writeObject(command, from, to)
+	default:
+		printUsage()
+	}
+
+	os.Exit(run(command, filename, action, printFunc))
+}
+
+// run reads the file and calls printFunc for each go object file. Returns the exit status.
+// If printFunc fails for the member of the archive, the other members are still processed.
+// The deferred functions are not run by os.Exit, so the file is closed here.
+func run(command, filename, action string, printFunc func(*goobj.File) error) int {
+	r, size, closer, err := open(filename)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to open %s: %v\n", filename, err)
-		os.Exit(1)
+		return 1
 	}
-	defer f.Close()
+	defer closer.Close()
 
-	file, err := goobj.Parse(f)
+	if !goobj.IsArchive(r) {
+		file, err := goobj.ParseReaderAt(r, size)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to parse goobj file: %v\n", err)
+			return 1
+		}
+
+		if err := printFunc(file); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to %s goobj file: %v\n", action, err)
+			return 1
+		}
+		return 0
+	}
+
+	if command == "strip" || command == "rewrite-paths" || command == "rename" || command == "retarget" {
+		fmt.Fprintf(os.Stderr, "failed to %s %s: the package archive is not supported\n", command, filename)
+		return 1
+	}
+
+	archive, err := goobj.ParseArchive(r, size)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to parse goobj file: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "failed to parse archive file: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	for _, member := range archive.Members {
+		if member.File == nil {
+			continue
+		}
+
+		fmt.Printf("Member %s:\n", member.Name)
+		if err := printFunc(member.File); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to %s goobj file %s: %v\n", action, member.Name, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// open returns the reader of the file and the closer which must be called after the reader is used.
+// Reads the entire standard input if the file name is -.
+func open(filename string) (io.ReaderAt, int64, io.Closer, error) {
+	if filename == "-" {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		return bytes.NewReader(data), int64(len(data)), ioutil.NopCloser(os.Stdin), nil
 	}
 
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, err
+	}
+	return f, info.Size(), f, nil
+}
+
+// verify prints the problems of the file, and returns the error if any problem is found.
+func verify(file *goobj.File) error {
+	err := file.Validate()
+	validationErr, ok := err.(*goobj.ValidationError)
+	if !ok {
+		if err == nil {
+			fmt.Println("No problem found")
+		}
+		return err
+	}
+
+	fmt.Println("The list of problems:")
+	for _, problem := range validationErr.Problems {
+		fmt.Printf(" %v\n", problem)
+	}
+	return fmt.Errorf("%d problem(s) found", len(validationErr.Problems))
+}
+
+func printSummary(file *goobj.File) {
+	goobj.PrintHeader(file)
+	goobj.PrintImports(file)
 	goobj.PrintSymbols(file)
 }
+
+// strip returns the function which writes the file without the symbols of the given kinds to the standard output.
+func strip(kinds []goobj.SymKind) func(*goobj.File) error {
+	return func(file *goobj.File) error {
+		stripped, err := file.Strip(kinds...)
+		if err != nil {
+			return err
+		}
+		_, err = stripped.WriteTo(os.Stdout)
+		return err
+	}
+}
+
+// parseKinds parses the --kinds option of the strip command, and returns the symbol kinds and the file name.
+// The DWARF kinds are returned if the option is omitted.
+func parseKinds() ([]goobj.SymKind, string) {
+	flags := flag.NewFlagSet("strip", flag.ExitOnError)
+	names := flags.String("kinds", "", "the comma-separated kinds of the symbols to be removed")
+	flags.Parse(os.Args[2:])
+	if flags.NArg() != 1 {
+		printUsage()
+	}
+
+	var kinds []goobj.SymKind
+	if *names == "" {
+		for kind := goobj.Sxxx; kind < math.MaxUint8; kind++ {
+			if kind.IsDWARF() {
+				kinds = append(kinds, kind)
+			}
+		}
+		return kinds, flags.Arg(0)
+	}
+
+	for _, name := range strings.Split(*names, ",") {
+		kind, ok := symKindByName(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown symbol kind: %s\n", name)
+			os.Exit(1)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, flags.Arg(0)
+}
+
+// symKindByName returns the symbol kind whose name is the given one, like SDWARFINFO.
+func symKindByName(name string) (goobj.SymKind, bool) {
+	for kind := goobj.Sxxx; kind < math.MaxUint8; kind++ {
+		if kind.String() == name {
+			return kind, true
+		}
+	}
+	return goobj.Sxxx, false
+}
+
+// parseFromTo parses the --from and --to options of the command, and returns them and the file name.
+func parseFromTo(command string) (string, string, string) {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	from := flags.String("from", "", "the path prefix or the symbol name to be changed")
+	to := flags.String("to", "", "the new path prefix or the new symbol name")
+	flags.Parse(os.Args[2:])
+	if flags.NArg() != 1 {
+		printUsage()
+	}
+	if *from == "" {
+		fmt.Fprintf(os.Stderr, "the --from option of %s must not be empty\n", command)
+		os.Exit(1)
+	}
+	return *from, *to, flags.Arg(0)
+}
+
+// writeObject returns the function which modifies the file according to the command and writes it to the standard output.
+func writeObject(command, from, to string) func(*goobj.File) error {
+	return func(file *goobj.File) error {
+		switch command {
+		case "rewrite-paths":
+			if _, err := file.RewritePaths(from, to); err != nil {
+				return err
+			}
+		case "rename":
+			if err := file.RenameSymbol(from, to); err != nil {
+				return err
+			}
+		case "retarget":
+			if _, err := file.RetargetRelocations(from, to); err != nil {
+				return err
+			}
+		}
+
+		_, err := file.WriteTo(os.Stdout)
+		return err
+	}
+}
+
+func printUsage() {
+	fmt.Printf(usage, os.Args[0], os.Args[0], os.Args[0])
+	os.Exit(1)
+}
+
+func withoutError(printFunc func(*goobj.File)) func(*goobj.File) error {
+	return func(file *goobj.File) error {
+		printFunc(file)
+		return nil
+	}
+}
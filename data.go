@@ -0,0 +1,126 @@
+package goobj
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SymbolData returns the content of the symbol. The returned slice shares the memory with the data block.
+// The content may be shorter than the symbol's size because the trailing zeros are not in the data block.
+func (f *File) SymbolData(symbol *Symbol) ([]byte, error) {
+	addr := symbol.DataAddr
+	if addr.Offset < 0 || addr.Size < 0 || addr.Offset+addr.Size > int64(len(f.DataBlock)) {
+		return nil, fmt.Errorf("data out of the data block: %+v", addr)
+	}
+	return f.DataBlock[addr.Offset : addr.Offset+addr.Size], nil
+}
+
+// RelocatedValue is the value the linker writes at the relocation site.
+type RelocatedValue struct {
+	// Target is the name of the symbol the relocation refers to.
+	Target string
+	Add    int64
+	Type   RelocType
+}
+
+// Segment is the part of the symbol's content. It is either the raw bytes or the relocation site.
+type Segment struct {
+	Offset int64
+	// Data is the bytes in the object file. For the relocation site, it is usually zeros and overwritten by the linker.
+	Data []byte
+	// Reloc is the relocation applied to the segment. Nil if the segment is the raw bytes.
+	Reloc *RelocatedValue
+}
+
+// RelocatedView is the view of the symbol's content which distinguishes the relocation sites from the raw bytes.
+type RelocatedView struct {
+	symbol   *Symbol
+	data     []byte
+	segments []Segment
+}
+
+// RelocatedView returns the view of the symbol's content.
+// The relocations which are out of the data, overlap the previous one or have no size, like R_CALLIND, are ignored.
+func (f *File) RelocatedView(symbol *Symbol) (*RelocatedView, error) {
+	data, err := f.SymbolData(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	relocs := make([]Relocation, len(symbol.Relocations))
+	copy(relocs, symbol.Relocations)
+	sort.SliceStable(relocs, func(i, j int) bool { return relocs[i].Offset < relocs[j].Offset })
+
+	view := &RelocatedView{symbol: symbol, data: data}
+	var offset int64
+	for _, reloc := range relocs {
+		if reloc.Size <= 0 || reloc.Offset < offset || reloc.Offset+reloc.Size > int64(len(data)) {
+			continue
+		}
+
+		if reloc.Offset > offset {
+			view.segments = append(view.segments, Segment{Offset: offset, Data: data[offset:reloc.Offset]})
+		}
+		value := &RelocatedValue{Target: reloc.Target(f), Add: reloc.Add, Type: reloc.Type}
+		view.segments = append(view.segments, Segment{Offset: reloc.Offset, Data: data[reloc.Offset : reloc.Offset+reloc.Size], Reloc: value})
+		offset = reloc.Offset + reloc.Size
+	}
+	if offset < int64(len(data)) {
+		view.segments = append(view.segments, Segment{Offset: offset, Data: data[offset:]})
+	}
+	return view, nil
+}
+
+// Segments returns the segments which cover the symbol's content in order.
+func (v *RelocatedView) Segments() []Segment {
+	return v.segments
+}
+
+// At returns the segment which contains the offset. The offset beyond the data but within the symbol's size
+// is in the raw segment of the zeros.
+func (v *RelocatedView) At(offset int64) (Segment, error) {
+	if offset < 0 || (offset >= int64(len(v.data)) && offset >= v.symbol.Size) {
+		return Segment{}, fmt.Errorf("offset out of the symbol: %#x", offset)
+	}
+	if offset >= int64(len(v.data)) {
+		return Segment{Offset: int64(len(v.data)), Data: make([]byte, v.symbol.Size-int64(len(v.data)))}, nil
+	}
+
+	i := sort.Search(len(v.segments), func(i int) bool {
+		return v.segments[i].Offset+int64(len(v.segments[i].Data)) > offset
+	})
+	return v.segments[i], nil
+}
+
+// ReadPointer reads the pointer-sized value at the offset of the symbol, in the byte order of the architecture.
+// If the pointer is the relocation site, the relocation is returned too, because the value in the object file
+// is usually zero and the actual value is determined by the linker.
+func (f *File) ReadPointer(symbol *Symbol, offset int64) (uint64, *RelocatedValue, error) {
+	data, err := f.SymbolData(symbol)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ptrSize := int64(f.Header.PtrSize())
+	if offset < 0 || offset+ptrSize > symbol.Size {
+		return 0, nil, fmt.Errorf("pointer out of the symbol: offset %#x, size %d", offset, symbol.Size)
+	}
+
+	var reloc *RelocatedValue
+	for _, r := range symbol.Relocations {
+		if r.Offset == offset && r.Size == ptrSize {
+			reloc = &RelocatedValue{Target: r.Target(f), Add: r.Add, Type: r.Type}
+			break
+		}
+	}
+
+	// the trailing zeros are not in the data block.
+	buff := make([]byte, ptrSize)
+	if offset < int64(len(data)) {
+		copy(buff, data[offset:])
+	}
+	if ptrSize == 4 {
+		return uint64(f.Header.ByteOrder().Uint32(buff)), reloc, nil
+	}
+	return f.Header.ByteOrder().Uint64(buff), reloc, nil
+}
@@ -0,0 +1,108 @@
+package goobj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFile_SymbolData(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	symbol := &file.Symbols[file.SymbolsByName()[`go.string."Hello, playground"`]]
+
+	data, err := file.SymbolData(symbol)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if string(data) != "Hello, playground" {
+		t.Errorf("data should be Hello, playground, but %q", data)
+	}
+
+	symbol.DataAddr.Size = int64(len(file.DataBlock))
+	if _, err := file.SymbolData(symbol); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_RelocatedView(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	symbol := b.AddSymbol(`"".data`, SDATA, []byte{1, 2, 0, 0, 0, 0, 0, 0, 0, 0, 3})
+	b.AddRelocation(symbol, Relocation{Offset: 2, Size: 8, Type: R_ADDR, Add: 4}, "target")
+	b.AddRelocation(symbol, Relocation{Offset: 0, Size: 0, Type: R_USETYPE}, "type.int")
+	symbol.Size = 16
+	file := b.Build()
+
+	view, err := file.RelocatedView(&file.Symbols[0])
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := []Segment{
+		{Offset: 0, Data: []byte{1, 2}},
+		{Offset: 2, Data: make([]byte, 8), Reloc: &RelocatedValue{Target: "target", Add: 4, Type: R_ADDR}},
+		{Offset: 10, Data: []byte{3}},
+	}
+	if !reflect.DeepEqual(expected, view.Segments()) {
+		t.Errorf("segments should be %+v, but %+v", expected, view.Segments())
+	}
+
+	for i, testData := range []struct {
+		offset   int64
+		expected Segment
+	}{
+		{offset: 1, expected: expected[0]},
+		{offset: 9, expected: expected[1]},
+		{offset: 10, expected: expected[2]},
+		{offset: 12, expected: Segment{Offset: 11, Data: make([]byte, 5)}},
+	} {
+		actual, err := view.At(testData.offset)
+		if err != nil {
+			t.Errorf("[%d] error should be nil, but %v", i, err)
+		}
+		if !reflect.DeepEqual(testData.expected, actual) {
+			t.Errorf("[%d] segment should be %+v, but %+v", i, testData.expected, actual)
+		}
+	}
+
+	if _, err := view.At(16); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_ReadPointer(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	// the string header: the pointer to the content and the length
+	symbol := &file.Symbols[file.SymbolsByName()[`"".statictmp_0`]]
+
+	_, reloc, err := file.ReadPointer(symbol, 0)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if reloc == nil || reloc.Target != `go.string."Hello, playground"` || reloc.Type != R_ADDR {
+		t.Errorf("invalid relocation: %+v", reloc)
+	}
+
+	length, reloc, err := file.ReadPointer(symbol, 8)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if length != 17 || reloc != nil {
+		t.Errorf("the length should be 17 without relocation, but %d, %+v", length, reloc)
+	}
+
+	if _, _, err := file.ReadPointer(symbol, 9); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_ReadPointer_BigEndian32(t *testing.T) {
+	b := NewObjectBuilder("linux", "mips", "go1.10")
+	b.AddSymbol(`"".data`, SDATA, []byte{0, 0, 1, 2})
+	b.AddSymbol(`"".bss`, SBSS, nil).Size = 4
+	file := b.Build()
+
+	if value, _, err := file.ReadPointer(&file.Symbols[0], 0); err != nil || value != 0x102 {
+		t.Errorf("the value should be 0x102, but %#x, %v", value, err)
+	}
+	if value, _, err := file.ReadPointer(&file.Symbols[1], 0); err != nil || value != 0 {
+		t.Errorf("the value should be 0, but %#x, %v", value, err)
+	}
+}
@@ -0,0 +1,90 @@
+package goobj
+
+import (
+	"fmt"
+	"io"
+)
+
+// Section represents the part of the object file.
+type Section int
+
+const (
+	// SectionHeader is the header line and the magic header.
+	SectionHeader Section = iota
+	// SectionDependencies is the list of the imported packages.
+	SectionDependencies
+	// SectionReferences is the list of the symbol references.
+	SectionReferences
+	// SectionData is the data block and its lengths.
+	SectionData
+	// SectionSymbols is the list of the defined symbols.
+	SectionSymbols
+	// SectionFooter is the magic footer.
+	SectionFooter
+)
+
+func (section Section) String() string {
+	switch section {
+	case SectionHeader:
+		return "header"
+	case SectionDependencies:
+		return "dependencies"
+	case SectionReferences:
+		return "references"
+	case SectionData:
+		return "data"
+	case SectionSymbols:
+		return "symbols"
+	case SectionFooter:
+		return "footer"
+	default:
+		return fmt.Sprintf("Section(%d)", int(section))
+	}
+}
+
+// ParseError is the error returned when the object file is malformed.
+type ParseError struct {
+	// Offset is the offset from the beginning of the file where the error is found.
+	Offset int64
+	// Section is the section being parsed.
+	Section Section
+	// SymbolIndex is the index of the symbol being parsed. -1 if the error is not in the symbols section.
+	SymbolIndex int
+	// SymbolName is the name of the symbol being parsed. Empty if the name is not known yet.
+	SymbolName string
+	// Err is the underlying error. io.ErrUnexpectedEOF if the file is truncated.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.SymbolIndex >= 0 && e.SymbolName != "":
+		return fmt.Sprintf("at offset %#x in the %s section (symbol %d %s): %v", e.Offset, e.Section, e.SymbolIndex, e.SymbolName, e.Err)
+	case e.SymbolIndex >= 0:
+		return fmt.Sprintf("at offset %#x in the %s section (symbol %d): %v", e.Offset, e.Section, e.SymbolIndex, e.Err)
+	default:
+		return fmt.Sprintf("at offset %#x in the %s section: %v", e.Offset, e.Section, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseError returns the ParseError which has the current position of the parser.
+func (p *parser) parseError(err error) error {
+	if parseErr, ok := err.(*ParseError); ok {
+		return parseErr
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	parseErr := &ParseError{Offset: p.reader.numReadBytes, Section: p.section, SymbolIndex: -1, Err: err}
+	if p.section == SectionSymbols {
+		parseErr.SymbolIndex = len(p.Symbols)
+		parseErr.SymbolName = p.symbolName
+	}
+	return parseErr
+}
@@ -0,0 +1,102 @@
+package goobj
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseBytes_ParseError(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".a`, SRODATA, []byte{1})
+	b.AddSymbol(`"".b`, SRODATA, []byte{2})
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	// break the marker of the 2nd symbol. The 1st symbol is 8 bytes: the marker, the kind,
+	// the reference, the flags, the size, the go type, the data size and the number of relocations.
+	file, _ := ParseBytes(obj)
+	secondSymbol := file.DataBlockPosition + file.Header.DataLength + 8
+	obj[secondSymbol] = 0x3
+
+	_, err = ParseBytes(obj)
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("error should be ParseError, but %#v", err)
+	}
+	if parseErr.Section != SectionSymbols || parseErr.SymbolIndex != 1 || parseErr.Offset != secondSymbol+1 {
+		t.Errorf("invalid error: %+v", parseErr)
+	}
+	if !strings.Contains(parseErr.Error(), "sanity check failed") {
+		t.Errorf("the error should have the cause: %v", parseErr)
+	}
+}
+
+func TestParseBytes_ParseError_SymbolName(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	symbol := b.AddSymbol(`"".main`, STEXT, []byte{0xc3})
+	b.AddRelocation(symbol, Relocation{Size: 4, Type: R_CALL}, "fmt.Println")
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	// truncated in the middle of the symbol
+	_, err = ParseBytes(obj[:len(obj)-len(magicFooter)-5])
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("error should be ParseError, but %#v", err)
+	}
+	if parseErr.SymbolIndex != 0 || parseErr.SymbolName != `"".main` {
+		t.Errorf("invalid error: %+v", parseErr)
+	}
+	if parseErr.Unwrap() != io.ErrUnexpectedEOF {
+		t.Errorf("the cause should be io.ErrUnexpectedEOF, but %v", parseErr.Unwrap())
+	}
+}
+
+func TestParseBytes_ParseError_Sections(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	file := parseFileForTesting(t, helloworldObjPath)
+
+	for i, testData := range []struct {
+		length   int64
+		expected Section
+	}{
+		{length: 10, expected: SectionHeader},
+		{length: file.DataBlockPosition - 30, expected: SectionReferences},
+		{length: file.DataBlockPosition - 2, expected: SectionData},
+		{length: file.DataBlockPosition + 10, expected: SectionData},
+		{length: int64(len(data)) - 3, expected: SectionFooter},
+	} {
+		_, err := ParseBytes(data[:testData.length])
+		parseErr, ok := err.(*ParseError)
+		if !ok {
+			t.Fatalf("[%d] error should be ParseError, but %#v", i, err)
+		}
+		if parseErr.Section != testData.expected {
+			t.Errorf("[%d] section should be %s, but %s", i, testData.expected, parseErr.Section)
+		}
+		if parseErr.SymbolIndex != -1 {
+			t.Errorf("[%d] symbol index should be -1, but %d", i, parseErr.SymbolIndex)
+		}
+	}
+}
+
+func TestParseBytes_ParseError_Indexed(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldIndexedObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	_, err = ParseBytes(data[:len(data)/2])
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("error should be ParseError, but %#v", err)
+	}
+}
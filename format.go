@@ -0,0 +1,239 @@
+package goobj
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// Format represents the format of the file, which is identified by its magic.
+type Format int
+
+const (
+	// FormatUnknown means the file is neither a go object file nor a package archive.
+	FormatUnknown Format = iota
+	// FormatArchive is the ar archive. See ParseArchive.
+	FormatArchive
+	// FormatGo13 is the object file format written by go1.5 and go1.6.
+	FormatGo13
+	// FormatGo17 is the object file format written by go1.7 and go1.8.
+	FormatGo17
+	// FormatGo19 is the object file format written by go1.9 to go1.15.
+	FormatGo19
+	// FormatIndexed is the object file format written by go1.16 and later.
+	FormatIndexed
+)
+
+func (format Format) String() string {
+	switch format {
+	case FormatUnknown:
+		return "unknown"
+	case FormatArchive:
+		return "archive"
+	case FormatGo13:
+		return "go13ld"
+	case FormatGo17:
+		return "go17ld"
+	case FormatGo19:
+		return "go19ld"
+	case FormatIndexed:
+		return "indexed"
+	default:
+		return fmt.Sprintf("Format(%d)", int(format))
+	}
+}
+
+// Toolchain returns the go releases which write the format. Empty if the format is not the go object file.
+func (format Format) Toolchain() string {
+	switch format {
+	case FormatGo13:
+		return "go1.5-go1.6"
+	case FormatGo17:
+		return "go1.7-go1.8"
+	case FormatGo19:
+		return "go1.9-go1.15"
+	case FormatIndexed:
+		return "go1.16 or later"
+	default:
+		return ""
+	}
+}
+
+// FormatReader reads the object file of the format which this package does not support.
+// See RegisterFormat.
+type FormatReader interface {
+	// Format returns the format the reader reads. It should be different from the formats defined in this package.
+	Format() Format
+	// Match returns true if the given magic header is the format's one. The length of the magic is 8 bytes.
+	Match(magic []byte) bool
+	// Read reads the rest of the object file into the file. The magic header is already read and
+	// the header line is already parsed into the file's Header and RawHeader.
+	Read(r io.Reader, file *File) error
+}
+
+// RegisterFormat adds the reader of the new object file format. Parse and Detect use the reader
+// when the file has its magic header. The formats this package supports are checked first.
+func RegisterFormat(reader FormatReader) {
+	formatReadersMu.Lock()
+	defer formatReadersMu.Unlock()
+
+	formatReaders = append(formatReaders, formatReader{
+		format: reader.Format(),
+		match:  reader.Match,
+		read: func(p *parser) error {
+			return reader.Read(&countingReader{reader: &p.reader}, &p.File)
+		},
+	})
+}
+
+// formatReader reads the object file of the specific format.
+type formatReader struct {
+	format Format
+	// match returns true if the given magic header is the format's one.
+	match func(magic []byte) bool
+	// read reads the rest of the object file. The magic header is already read.
+	read func(p *parser) error
+}
+
+// formatReaders is the list of the supported object file formats, followed by the registered ones.
+var (
+	formatReaders = []formatReader{
+		{format: FormatGo13, match: equalTo(magicHeaderGo13), read: (*parser).readGo13},
+		{format: FormatGo17, match: equalTo(magicHeaderGo17), read: (*parser).readGo19},
+		{format: FormatGo19, match: equalTo(magicHeader), read: (*parser).readGo19},
+		{format: FormatIndexed, match: isIndexedMagic, read: func(p *parser) error { return p.parseIndexed(p.magic) }},
+	}
+	formatReadersMu sync.RWMutex
+)
+
+func equalTo(magic []byte) func([]byte) bool {
+	return func(b []byte) bool { return bytes.Equal(b, magic) }
+}
+
+// findFormatReader returns the reader of the format whose magic header is the given one.
+func findFormatReader(magic []byte) (formatReader, bool) {
+	formatReadersMu.RLock()
+	defer formatReadersMu.RUnlock()
+
+	for _, reader := range formatReaders {
+		if reader.match(magic) {
+			return reader, true
+		}
+	}
+	return formatReader{}, false
+}
+
+// countingReader is io.Reader which reads from readerWithCounter, so that the number of read bytes is recorded.
+type countingReader struct {
+	reader *readerWithCounter
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	if r.reader.err != nil {
+		return 0, r.reader.err
+	}
+
+	n, err := r.reader.raw.Read(p)
+	r.reader.numReadBytes += int64(n)
+	return n, err
+}
+
+// the magic of the go object file looks like this regardless of the format.
+var goObjectMagicPattern = regexp.MustCompile(`^\x00(\x00go1[0-9]|go1[0-9]{2})ld$`)
+
+// Detect reads the given reader until the format of the file is identified.
+// FormatUnknown and nil error are returned if the file is not a go object file nor a package archive.
+// The error is returned if the file is the go object file, but its format is not supported.
+func Detect(r io.Reader) (Format, error) {
+	p := newParser(bufio.NewReader(r))
+	if err := p.detect(); err != nil {
+		return FormatUnknown, err
+	}
+	return p.Format, nil
+}
+
+// detect sets the format of the file. If the file is the go object file, the magic header is read
+// and the header line is parsed.
+func (p *parser) detect() error {
+	buff := make([]byte, len(magicHeader))
+	_ = p.reader.read(buff)
+	if p.reader.atEOF() {
+		// too short to be the go object file.
+		return nil
+	} else if p.reader.err != nil {
+		return p.reader.err
+	}
+	if bytes.Equal(buff, archiveMagic) {
+		p.Format = FormatArchive
+		return nil
+	}
+
+	// the 1st line is kept to parse the header line.
+	var line []byte
+	lineEnded := false
+	for {
+		if reader, ok := findFormatReader(buff); ok {
+			p.Format, p.read = reader.format, reader.read
+			break
+		}
+		if goObjectMagicPattern.Match(buff) {
+			return p.unsupportedFormatError(line, bytes.TrimLeft(buff, "\x00"))
+		}
+
+		b := p.reader.readByte()
+		if p.reader.err == io.EOF {
+			if !lineEnded {
+				// the rest of the line is still in the buffer
+				line = append(line, buff...)
+				if i := bytes.IndexByte(line, '\n'); i >= 0 {
+					line = line[:i]
+				}
+			}
+			return p.unsupportedFormatError(line, nil)
+		} else if p.reader.err != nil {
+			return p.reader.err
+		}
+
+		p.RawHeader = append(p.RawHeader, buff[0])
+		if limit := p.reader.limit().MaxHeaderSize; int64(len(p.RawHeader)) > limit {
+			return fmt.Errorf("too large header: more than %d bytes before the magic header", limit)
+		}
+		if buff[0] == '\n' {
+			lineEnded = true
+		} else if !lineEnded && len(line) < maxHeaderLineLength {
+			line = append(line, buff[0])
+		}
+		if (lineEnded || len(line) >= len(goObjectPrefix)) && !bytes.HasPrefix(line, goObjectPrefix) {
+			// not the go object file. Stops here rather than reading the whole file to find the magic header.
+			return nil
+		}
+		buff = append(buff[1:], b)
+	}
+
+	if lineEnded {
+		p.parseHeaderLine(string(line))
+	}
+	p.magic = buff
+	return nil
+}
+
+// unsupportedFormatError returns the error if the file looks like the go object file. Otherwise returns nil.
+func (p *parser) unsupportedFormatError(line, magic []byte) error {
+	if bytes.HasPrefix(line, goObjectPrefix) {
+		p.parseHeaderLine(string(line))
+	}
+
+	switch {
+	case magic != nil && p.Header.GoVersion != "":
+		return fmt.Errorf("unsupported object file format %s built by %s", magic, p.Header.GoVersion)
+	case magic != nil:
+		return fmt.Errorf("unsupported object file format %s", magic)
+	case p.Header.GoVersion != "":
+		return fmt.Errorf("magic header not found in the object file built by %s", p.Header.GoVersion)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,162 @@
+package goobj
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	for i, testData := range []struct {
+		path     string
+		expected Format
+	}{
+		{path: helloworldObjPath, expected: FormatGo19},
+		{path: helloworldIndexedObjPath, expected: FormatIndexed},
+	} {
+		obj, err := ioutil.ReadFile(testData.path)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+
+		actual, err := Detect(bytes.NewReader(obj))
+		if err != nil {
+			t.Errorf("[%d] error should be nil, but %v", i, err)
+		}
+		if actual != testData.expected {
+			t.Errorf("[%d] format should be %s, but %s", i, testData.expected, actual)
+		}
+	}
+}
+
+func TestDetect_Magic(t *testing.T) {
+	for i, testData := range []struct {
+		in       string
+		expected Format
+	}{
+		{in: "go object linux amd64 go1.6 X:none\n!\n\x00\x00go13ld\x01", expected: FormatGo13},
+		{in: "\x00\x00go17ld", expected: FormatGo17},
+		{in: "\x00go116ld", expected: FormatIndexed},
+		{in: string(archiveForTesting(nil)), expected: FormatArchive},
+		{in: "not a go object file", expected: FormatUnknown},
+		{in: "", expected: FormatUnknown},
+	} {
+		actual, err := Detect(strings.NewReader(testData.in))
+		if err != nil {
+			t.Errorf("[%d] error should be nil, but %v", i, err)
+		}
+		if actual != testData.expected {
+			t.Errorf("[%d] format should be %s, but %s", i, testData.expected, actual)
+		}
+	}
+}
+
+// endlessReader returns the same byte forever and counts the bytes read.
+type endlessReader struct {
+	b    byte
+	read int64
+}
+
+func (r *endlessReader) Read(buff []byte) (int, error) {
+	for i := range buff {
+		buff[i] = r.b
+	}
+	r.read += int64(len(buff))
+	return len(buff), nil
+}
+
+func TestDetect_LargeNonGoInput(t *testing.T) {
+	r := &endlessReader{b: 'a'}
+	format, err := Detect(r)
+	if err != nil || format != FormatUnknown {
+		t.Errorf("format should be unknown, but %s (%v)", format, err)
+	}
+	if r.read > 1<<16 {
+		t.Errorf("the whole input should not be read, but %d bytes are read", r.read)
+	}
+
+	r = &endlessReader{b: 'a'}
+	if _, err := ParseReader(r); err == nil || strings.Contains(err.Error(), "too large header") {
+		t.Errorf("error should be the magic header not found, but %v", err)
+	}
+}
+
+func TestDetect_UnsupportedFormat(t *testing.T) {
+	for i, testData := range []struct {
+		in       string
+		expected string
+	}{
+		{in: "go object linux amd64 go1.15\n!\n\x00go115ld", expected: "unsupported object file format go115ld built by go1.15"},
+		{in: "\x00\x00go12ld", expected: "unsupported object file format go12ld"},
+		{in: "go object linux amd64 go1.4\n!\n", expected: "magic header not found in the object file built by go1.4"},
+	} {
+		_, err := Detect(strings.NewReader(testData.in))
+		if err == nil || err.Error() != testData.expected {
+			t.Errorf("[%d] error should be %q, but %v", i, testData.expected, err)
+		}
+	}
+}
+
+func TestParseBytes_Archive(t *testing.T) {
+	if _, err := ParseBytes(archiveForTesting(nil)); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestParser_checkVersion_ErrorMessage(t *testing.T) {
+	p := newParser(bufio.NewReader(strings.NewReader("\x02")))
+	p.Format = FormatGo19
+	err := p.checkVersion()
+	expected := "unexpected version of the go19ld format (go1.9-go1.15): 2"
+	if err == nil || err.Error() != expected {
+		t.Errorf("error should be %q, but %v", expected, err)
+	}
+}
+
+// testFormatReader reads the test format, which consists of the magic and the imports separated by the spaces.
+type testFormatReader struct{}
+
+const formatForTesting Format = 100
+
+func (testFormatReader) Format() Format { return formatForTesting }
+
+func (testFormatReader) Match(magic []byte) bool { return string(magic) == "\x00test1ld" }
+
+func (testFormatReader) Read(r io.Reader, file *File) error {
+	rest, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	file.Imports = strings.Fields(string(rest))
+	if len(file.Imports) == 0 {
+		return errors.New("no imports")
+	}
+	return nil
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat(testFormatReader{})
+
+	in := "go object linux amd64 go1.99\n!\n\x00test1ld fmt os"
+	format, err := Detect(strings.NewReader(in))
+	if err != nil || format != formatForTesting {
+		t.Errorf("format should be %s, but %s (%v)", formatForTesting, format, err)
+	}
+
+	file, err := ParseBytes([]byte(in))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if !reflect.DeepEqual([]string{"fmt", "os"}, file.Imports) || file.Header.GoVersion != "go1.99" {
+		t.Errorf("invalid file: %+v", file)
+	}
+
+	if _, err := ParseBytes([]byte("\x00test1ld")); err == nil {
+		t.Errorf("the error of the reader should be returned")
+	}
+}
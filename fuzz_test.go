@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+package goobj
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, path := range []string{helloworldObjPath, helloworldIndexedObjPath} {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			f.Fatalf("failed to read file: %v", err)
+		}
+		f.Add(data)
+	}
+
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	symbol := b.AddSymbol(`"".main`, STEXT, []byte{0xc3})
+	b.AddRelocation(symbol, Relocation{Size: 4, Type: R_CALL}, "fmt.Println")
+	_ = b.AddFile(symbol, "/a.go")
+	obj, err := b.Bytes()
+	if err != nil {
+		f.Fatalf("failed to build the object: %v", err)
+	}
+	f.Add(obj)
+	f.Add([]byte("\x00\x00go13ld\x01\x00\xfe\x02\x02a\x00\x00\x02\x00\x00\x02\xc3\x00\xff\xffgo13ld"))
+	f.Add([]byte("\x00\x00go17ld\x01\x00\xff\x00\x00\x00\x00\x00\x00\xff\xffgo17ld"))
+
+	limits := Limits{MaxDataSize: 1 << 20, MaxObjectSize: 1 << 20, MaxHeaderSize: 1 << 20, MaxStringLength: 1 << 16, MaxRelocations: 1 << 16}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = Detect(bytes.NewReader(data))
+		_, _ = ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{Limits: limits})
+		_, _ = ParseReaderWithOptions(bytes.NewReader(data), ParseOptions{Limits: limits, Lenient: true})
+	})
+}
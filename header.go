@@ -0,0 +1,62 @@
+package goobj
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+const (
+	headerLinePrefix    = "go object "
+	maxHeaderLineLength = 1024
+)
+
+// parseHeaderLine parses the line like `go object linux amd64 go1.10 X:framepointer`.
+// The line is ignored if it is not the header line.
+func (p *parser) parseHeaderLine(line string) {
+	if !strings.HasPrefix(line, headerLinePrefix) {
+		return
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, headerLinePrefix))
+	for i, field := range fields {
+		switch {
+		case i == 0:
+			p.Header.GOOS = field
+		case i == 1:
+			p.Header.GOARCH = field
+		case i == 2:
+			p.Header.GoVersion = field
+		case strings.HasPrefix(field, "X:"):
+			for _, experiment := range strings.Split(strings.TrimPrefix(field, "X:"), ",") {
+				if experiment != "" && experiment != "none" {
+					p.Header.Experiments = append(p.Header.Experiments, experiment)
+				}
+			}
+		}
+	}
+}
+
+// PtrSize returns the pointer size of the architecture. Returns 8 if the architecture is unknown.
+func (h Header) PtrSize() int {
+	switch h.GOARCH {
+	case "386", "amd64p32", "arm", "mips", "mipsle":
+		return 4
+	default:
+		return 8
+	}
+}
+
+// ByteOrder returns the byte order of the architecture. Returns little endian if the architecture is unknown.
+func (h Header) ByteOrder() binary.ByteOrder {
+	switch h.GOARCH {
+	case "mips", "mips64", "ppc64", "s390x", "sparc64":
+		return binary.BigEndian
+	default:
+		return binary.LittleEndian
+	}
+}
+
+// PCQuantum returns the minimum instruction size of the architecture.
+func (h Header) PCQuantum() int64 {
+	return PCQuantum(h.GOARCH)
+}
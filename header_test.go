@@ -0,0 +1,72 @@
+package goobj
+
+import (
+	"bufio"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser_parseHeaderLine(t *testing.T) {
+	for i, testData := range []struct {
+		in       string
+		expected Header
+	}{
+		{
+			in:       "go object darwin amd64 go1.10 X:framepointer",
+			expected: Header{GOOS: "darwin", GOARCH: "amd64", GoVersion: "go1.10", Experiments: []string{"framepointer"}},
+		},
+		{
+			in:       "go object linux arm go1.9 X:none",
+			expected: Header{GOOS: "linux", GOARCH: "arm", GoVersion: "go1.9"},
+		},
+		{
+			in:       "go object linux amd64 go1.21.0 GOAMD64=v1 X:regabiwrappers,regabiargs",
+			expected: Header{GOOS: "linux", GOARCH: "amd64", GoVersion: "go1.21.0", Experiments: []string{"regabiwrappers", "regabiargs"}},
+		},
+		{
+			in:       "not a header line",
+			expected: Header{},
+		},
+	} {
+		p := newParser(bufio.NewReader(strings.NewReader("")))
+		p.parseHeaderLine(testData.in)
+		if !reflect.DeepEqual(testData.expected, p.Header) {
+			t.Errorf("[%d] the header should be %+v, but %+v", i, testData.expected, p.Header)
+		}
+	}
+}
+
+func TestParser_skipHeader_HeaderLine(t *testing.T) {
+	p := newParser(bufio.NewReader(strings.NewReader("go object linux 386 go1.10 X:none\n!\n\x00\x00go19ld")))
+	if err := p.skipHeader(); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if p.Header.GOOS != "linux" || p.Header.GOARCH != "386" || p.Header.GoVersion != "go1.10" {
+		t.Errorf("invalid header: %+v", p.Header)
+	}
+}
+
+func TestParse_Header(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	if file.Header.GOOS != "darwin" || file.Header.GOARCH != "amd64" || file.Header.GoVersion != "go1.10" {
+		t.Errorf("invalid header: %+v", file.Header)
+	}
+}
+
+func TestHeader_PtrSize(t *testing.T) {
+	for goarch, expected := range map[string]int{"amd64": 8, "386": 4, "arm": 4, "arm64": 8, "": 8} {
+		if actual := (Header{GOARCH: goarch}).PtrSize(); actual != expected {
+			t.Errorf("[%s] pointer size should be %d, but %d", goarch, expected, actual)
+		}
+	}
+}
+
+func TestHeader_ByteOrder(t *testing.T) {
+	for goarch, expected := range map[string]binary.ByteOrder{"amd64": binary.LittleEndian, "s390x": binary.BigEndian, "ppc64le": binary.LittleEndian} {
+		if actual := (Header{GOARCH: goarch}).ByteOrder(); actual != expected {
+			t.Errorf("[%s] byte order should be %v, but %v", goarch, expected, actual)
+		}
+	}
+}
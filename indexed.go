@@ -0,0 +1,821 @@
+package goobj
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+)
+
+// The indexed object file format is used by go1.16 and later. Unlike the go19ld format,
+// it consists of the fixed-size tables which refer to each other by the index.
+// See cmd/internal/goobj/objfile.go for the details.
+
+// the magic of the indexed format is like "\x00go120ld". The 2 digits are the minor version of the go release
+// which introduced the layout.
+const (
+	indexedMagicPrefix = "\x00go1"
+	indexedMagicSuffix = "ld"
+	indexedMagicLength = 8
+)
+
+// the minimum minor version which supports each change of the layout.
+const (
+	indexedVersionMin = 16
+	// the pcdata tables became the aux symbols and the pcdata block was removed.
+	indexedVersionPCDataAux = 17
+	// the func data offsets were removed from the FuncInfo and the start line was added.
+	indexedVersionStartLine = 20
+)
+
+// taken from go1.20 cmd/internal/goobj
+const (
+	indexedPkgIdxNone     = (1<<31 - 1) - iota // Non-package symbols
+	indexedPkgIdxHashed64                      // Short hashed (content-addressable) symbols
+	indexedPkgIdxHashed                        // Hashed (content-addressable) symbols
+	indexedPkgIdxBuiltin                       // Predefined runtime symbols (ex: runtime.newobject)
+	indexedPkgIdxSelf                          // Symbols defined in the current package
+)
+
+// Blocks. Go1.16 has the pcdata block between the data block and the refname block.
+const (
+	blkAutolib = iota
+	blkPkgIdx
+	blkFile
+	blkSymdef
+	blkHashed64def
+	blkHasheddef
+	blkNonpkgdef
+	blkNonpkgref
+	blkRefFlags
+	blkHash64
+	blkHash
+	blkRelocIdx
+	blkAuxIdx
+	blkDataIdx
+	blkReloc
+	blkAux
+	blkData
+	// go1.16 only
+	blkPcdata
+)
+
+const (
+	indexedStringRefSize = 8
+	indexedSymSize       = indexedStringRefSize + 2 + 1 + 1 + 1 + 4 + 4
+	indexedAuxSize       = 1 + 8
+	indexedRefNameSize   = 8 + indexedStringRefSize
+	// the type of the relocation is uint8 in the older releases and uint16 in the newer ones.
+	indexedRelocSizeUint8Type  = 4 + 1 + 1 + 8 + 8
+	indexedRelocSizeUint16Type = 4 + 1 + 2 + 8 + 8
+)
+
+const indexedSymABIStatic = ^uint16(0)
+
+// Sym.Flag
+const (
+	indexedSymFlagDupok = 1 << iota
+	indexedSymFlagLocal
+	indexedSymFlagTypelink
+	indexedSymFlagLeaf
+	indexedSymFlagNoSplit
+	indexedSymFlagReflectMethod
+)
+
+const indexedObjFlagShared = 1
+
+// Aux Type
+const (
+	auxGotype = iota
+	auxFuncInfo
+	auxFuncdata
+	auxDwarfInfo
+	auxDwarfLoc
+	auxDwarfRanges
+	auxDwarfLines
+	auxPcsp
+	auxPcfile
+	auxPcline
+	auxPcinline
+	auxPcdata
+)
+
+// symRef is the reference to the symbol in the indexed format.
+type symRef struct {
+	pkgIdx, symIdx uint32
+}
+
+func isIndexedMagic(b []byte) bool {
+	_, ok := indexedMagicVersion(b)
+	return ok
+}
+
+// indexedMagicVersion returns the minor version in the magic.
+func indexedMagicVersion(b []byte) (int, bool) {
+	if len(b) != indexedMagicLength || string(b[:len(indexedMagicPrefix)]) != indexedMagicPrefix ||
+		string(b[indexedMagicLength-len(indexedMagicSuffix):]) != indexedMagicSuffix {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(string(b[len(indexedMagicPrefix) : indexedMagicLength-len(indexedMagicSuffix)]))
+	if err != nil || version < indexedVersionMin {
+		return 0, false
+	}
+	return version, true
+}
+
+// parseIndexed parses the rest of the indexed format object file. The magic header is already read.
+func (p *parser) parseIndexed(magic []byte) error {
+	magicPosition := p.reader.numReadBytes - int64(len(magic))
+	limit := p.reader.limit().MaxObjectSize
+	rest, err := ioutil.ReadAll(io.LimitReader(p.reader.raw, limit+1))
+	if err != nil {
+		return err
+	}
+	p.reader.numReadBytes += int64(len(rest))
+	if int64(len(rest)) > limit {
+		return fmt.Errorf("too large object file: more than %d bytes", limit)
+	}
+
+	version, _ := indexedMagicVersion(magic)
+	r := &indexedReader{
+		obj:            append(magic, rest...),
+		version:        version,
+		magicPosition:  magicPosition,
+		maxRelocations: p.reader.limit().MaxRelocations,
+		lenient:        p.lenient,
+	}
+	if err := r.readHeader(); err != nil {
+		return r.parseError(SectionHeader, 0, -1, err)
+	}
+	return r.readFile(&p.File)
+}
+
+// indexedReader reads the indexed format object file in memory. If raw is not nil, obj holds only the blocks
+// before the data block, and the data block and the blocks after it are read from raw when necessary.
+type indexedReader struct {
+	obj     []byte
+	raw     io.ReaderAt
+	version int
+	// the offset of the magic header from the beginning of the file.
+	magicPosition  int64
+	maxRelocations int64
+	lenient        bool
+	flags          uint32
+	offsets        []uint32
+
+	numDefs, numNonpkgdefs, numNonpkgrefs int
+	relocSize                             int
+	pkgPaths                              []string
+	refNames                              map[symRef]string
+	// the index of SymbolReferences for each referenced symbol
+	refIndices map[symRef]int64
+	// the index of SymbolReferences for each file
+	fileRefIndices []int64
+}
+
+func (r *indexedReader) numBlocks() int {
+	if r.version < indexedVersionPCDataAux {
+		// plus the pcdata block
+		return blkData + 4
+	}
+	return blkData + 3
+}
+
+func (r *indexedReader) blkRefName() int {
+	return r.numBlocks() - 2
+}
+
+// headerSize returns the size of the magic, the fingerprint, the flags and the offsets of the blocks.
+func (r *indexedReader) headerSize() int {
+	return indexedMagicLength + 8 + 4 + 4*r.numBlocks()
+}
+
+func (r *indexedReader) readHeader() error {
+	if err := r.readOffsets(uint32(len(r.obj))); err != nil {
+		return err
+	}
+	return r.readCounts()
+}
+
+// readOffsets reads the flags and the offsets of the blocks. The offsets must not exceed the size of the object file.
+func (r *indexedReader) readOffsets(size uint32) error {
+	if len(r.obj) < r.headerSize() {
+		return errors.New("too short indexed object file header")
+	}
+	// magic and fingerprint
+	off := indexedMagicLength + 8
+	r.flags = binary.LittleEndian.Uint32(r.obj[off:])
+	off += 4
+
+	for i := 0; i < r.numBlocks(); i++ {
+		offset := binary.LittleEndian.Uint32(r.obj[off:])
+		if offset > size || (i > 0 && offset < r.offsets[i-1]) {
+			return fmt.Errorf("invalid offset of the block %d: %#x", i, offset)
+		}
+		r.offsets = append(r.offsets, offset)
+		off += 4
+	}
+	return nil
+}
+
+// readCounts counts the symbols and the size of the relocation from the offsets.
+func (r *indexedReader) readCounts() error {
+
+	r.numDefs = int(r.blockSize(blkSymdef)+r.blockSize(blkHashed64def)+r.blockSize(blkHasheddef)+r.blockSize(blkNonpkgdef)) / indexedSymSize
+	r.numNonpkgdefs = int(r.blockSize(blkNonpkgdef)) / indexedSymSize
+	r.numNonpkgrefs = int(r.blockSize(blkNonpkgref)) / indexedSymSize
+	if int(r.blockSize(blkDataIdx)) != 4*(r.numDefs+1) {
+		return fmt.Errorf("the size of the data index block mismatch: %d symbols, %d bytes", r.numDefs, r.blockSize(blkDataIdx))
+	}
+
+	numRelocs := int(r.indexAt(blkRelocIdx, r.numDefs))
+	r.relocSize = indexedRelocSizeUint16Type
+	if numRelocs > 0 {
+		r.relocSize = int(r.blockSize(blkReloc)) / numRelocs
+	}
+	if r.relocSize != indexedRelocSizeUint8Type && r.relocSize != indexedRelocSizeUint16Type {
+		return fmt.Errorf("unexpected relocation size: %d", r.relocSize)
+	}
+	return nil
+}
+
+// load reads the header and the blocks before the data block from raw. limit is the maximum size of them.
+func (r *indexedReader) load(limit int64) error {
+	r.obj = make([]byte, r.headerSize())
+	if err := r.readAt(r.obj, 0); err != nil {
+		return err
+	}
+	// the size of the object file is unknown here. Reading the blocks fails if the offsets exceed the file.
+	if err := r.readOffsets(math.MaxUint32); err != nil {
+		return err
+	}
+
+	size := r.offsets[blkData]
+	if int64(size) > limit {
+		return fmt.Errorf("too large object file: %d bytes before the data block (limit %d)", size, limit)
+	}
+	if size < uint32(len(r.obj)) {
+		return fmt.Errorf("invalid offset of the data block: %#x", size)
+	}
+	obj := make([]byte, size)
+	copy(obj, r.obj)
+	if err := r.readAt(obj[len(r.obj):], int64(len(r.obj))); err != nil {
+		return err
+	}
+	r.obj = obj
+	return r.readCounts()
+}
+
+// readAt reads len(b) bytes at the given offset from the magic header.
+func (r *indexedReader) readAt(b []byte, off int64) error {
+	_, err := io.ReadFull(io.NewSectionReader(r.raw, r.magicPosition+off, int64(len(b))), b)
+	return err
+}
+
+// block returns the content of the block, which is read from raw if it is not in memory.
+func (r *indexedReader) block(blk int) ([]byte, error) {
+	start, end := r.offsets[blk], r.offsets[blk+1]
+	if r.raw == nil || end <= uint32(len(r.obj)) {
+		return r.obj[start:end], nil
+	}
+
+	b := make([]byte, end-start)
+	if err := r.readAt(b, int64(start)); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readData returns the data at the given address of the data block.
+func (r *indexedReader) readData(addr DataAddr) ([]byte, error) {
+	start := int64(r.offsets[blkData]) + addr.Offset
+	if r.raw == nil {
+		return r.obj[start : start+addr.Size], nil
+	}
+
+	b := make([]byte, addr.Size)
+	if err := r.readAt(b, start); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// dataSize returns the size of the data block. The pcdata block of go1.16 is included.
+func (r *indexedReader) dataSize() int64 {
+	if r.version < indexedVersionPCDataAux {
+		return int64(r.offsets[blkPcdata+1] - r.offsets[blkData])
+	}
+	return int64(r.blockSize(blkData))
+}
+
+func (r *indexedReader) blockSize(blk int) uint32 {
+	return r.offsets[blk+1] - r.offsets[blk]
+}
+
+func (r *indexedReader) uint32At(off uint32) uint32 {
+	if uint64(off)+4 > uint64(len(r.obj)) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(r.obj[off:])
+}
+
+// indexAt returns the i-th element of the index block (reloc, aux or data index).
+func (r *indexedReader) indexAt(blk, i int) uint32 {
+	return r.uint32At(r.offsets[blk] + uint32(4*i))
+}
+
+func (r *indexedReader) stringAt(off uint32) (string, error) {
+	return r.string(r.uint32At(off), r.uint32At(off+4))
+}
+
+// string returns the string in the string table, which is at the beginning of the object file.
+func (r *indexedReader) string(length, strOff uint32) (string, error) {
+	if uint64(strOff)+uint64(length) > uint64(len(r.obj)) {
+		return "", fmt.Errorf("string out of the object file: offset %#x, length %d", strOff, length)
+	}
+	return string(r.obj[strOff : strOff+length]), nil
+}
+
+// readFile reads the file. In the lenient mode, the broken symbols are recorded as the diagnostics and skipped.
+func (r *indexedReader) readFile(file *File) error {
+	if err := r.readImports(file); err != nil {
+		if err := r.recover(file, r.parseError(SectionDependencies, r.offsets[blkAutolib], -1, err)); err != nil {
+			return err
+		}
+	}
+	if err := r.readReferences(file); err != nil {
+		if err := r.recover(file, r.parseError(SectionReferences, r.offsets[blkPkgIdx], -1, err)); err != nil {
+			return err
+		}
+	}
+
+	// the pcdata block of go1.16 is appended to the data block, so that the pcvalue tables are in the data block
+	// like the other formats.
+	start := int64(r.offsets[blkData])
+	file.DataBlock = r.obj[start : start+r.dataSize()]
+	file.DataBlockPosition = r.magicPosition + start
+
+	for i := 0; i < r.numDefs; i++ {
+		symbol, err := r.readSymbol(file, i)
+		if err != nil {
+			if err := r.recover(file, r.symbolError(file, i, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		file.Symbols = append(file.Symbols, symbol)
+	}
+
+	file.setLengths()
+	return nil
+}
+
+// symbolError returns the ParseError of the i-th symbol.
+func (r *indexedReader) symbolError(file *File, i int, err error) *ParseError {
+	parseErr := r.parseError(SectionSymbols, r.offsets[blkSymdef]+uint32(i*indexedSymSize), i, err)
+	parseErr.SymbolName = file.referenceName(r.defRefIndex(i))
+	return parseErr
+}
+
+// recover records the error as the diagnostic in the lenient mode. Otherwise returns the error.
+func (r *indexedReader) recover(file *File, err *ParseError) error {
+	if !r.lenient {
+		return err
+	}
+	file.Diagnostics = append(file.Diagnostics, err)
+	return nil
+}
+
+// parseError returns the ParseError at the given offset from the magic header.
+func (r *indexedReader) parseError(section Section, off uint32, symbolIndex int, err error) *ParseError {
+	return &ParseError{Offset: r.magicPosition + int64(off), Section: section, SymbolIndex: symbolIndex, Err: err}
+}
+
+func (r *indexedReader) readImports(file *File) error {
+	// the pkg name and the fingerprint
+	const importedPkgSize = indexedStringRefSize + 8
+	for off := r.offsets[blkAutolib]; off+importedPkgSize <= r.offsets[blkAutolib+1]; off += importedPkgSize {
+		pkg, err := r.stringAt(off)
+		if err != nil {
+			return err
+		}
+		file.Imports = append(file.Imports, pkg)
+	}
+	return nil
+}
+
+// readReferences adds the defined symbols, the non-package references and the files to the SymbolReferences.
+// The references to the other packages' symbols are added when they are referred.
+func (r *indexedReader) readReferences(file *File) error {
+	file.SymbolReferences = append(file.SymbolReferences, SymbolReference{})
+	r.refIndices = make(map[symRef]int64)
+
+	for _, def := range []struct {
+		blk    int
+		pkgIdx uint32
+	}{
+		{blkSymdef, indexedPkgIdxSelf},
+		{blkHashed64def, indexedPkgIdxHashed64},
+		{blkHasheddef, indexedPkgIdxHashed},
+		{blkNonpkgdef, indexedPkgIdxNone},
+	} {
+		numSyms := int(r.blockSize(def.blk)) / indexedSymSize
+		for i := 0; i < numSyms; i++ {
+			if err := r.addSymbolReference(file, symRef{def.pkgIdx, uint32(i)}, r.offsets[def.blk]+uint32(i*indexedSymSize)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := 0; i < r.numNonpkgrefs; i++ {
+		ref := symRef{indexedPkgIdxNone, uint32(r.numNonpkgdefs + i)}
+		if err := r.addSymbolReference(file, ref, r.offsets[blkNonpkgref]+uint32(i*indexedSymSize)); err != nil {
+			return err
+		}
+	}
+
+	for off := r.offsets[blkPkgIdx]; off+indexedStringRefSize <= r.offsets[blkPkgIdx+1]; off += indexedStringRefSize {
+		pkgPath, err := r.stringAt(off)
+		if err != nil {
+			return err
+		}
+		r.pkgPaths = append(r.pkgPaths, pkgPath)
+	}
+
+	r.refNames = make(map[symRef]string)
+	refNames, err := r.block(r.blkRefName())
+	if err != nil {
+		return err
+	}
+	for b := refNames; len(b) >= indexedRefNameSize; b = b[indexedRefNameSize:] {
+		name, err := r.string(binary.LittleEndian.Uint32(b[8:]), binary.LittleEndian.Uint32(b[12:]))
+		if err != nil {
+			return err
+		}
+		r.refNames[symRef{binary.LittleEndian.Uint32(b), binary.LittleEndian.Uint32(b[4:])}] = name
+	}
+
+	for off := r.offsets[blkFile]; off+indexedStringRefSize <= r.offsets[blkFile+1]; off += indexedStringRefSize {
+		name, err := r.stringAt(off)
+		if err != nil {
+			return err
+		}
+		file.SymbolReferences = append(file.SymbolReferences, SymbolReference{Name: fileSymbolPrefix + name})
+		r.fileRefIndices = append(r.fileRefIndices, int64(len(file.SymbolReferences)-1))
+	}
+	return nil
+}
+
+func (r *indexedReader) addSymbolReference(file *File, ref symRef, off uint32) error {
+	name, err := r.stringAt(off)
+	if err != nil {
+		return err
+	}
+
+	abi := binary.LittleEndian.Uint16(r.obj[off+8:])
+	reference := SymbolReference{Name: name}
+	if abi == indexedSymABIStatic {
+		reference.Version = 1
+	} else {
+		reference.ABI = int64(abi)
+	}
+
+	file.SymbolReferences = append(file.SymbolReferences, reference)
+	r.refIndices[ref] = int64(len(file.SymbolReferences) - 1)
+	return nil
+}
+
+// refIndex returns the index of SymbolReferences for the symbol reference.
+func (r *indexedReader) refIndex(file *File, ref symRef) (int64, error) {
+	if ref == (symRef{}) {
+		return 0, nil
+	}
+	if index, ok := r.refIndices[ref]; ok {
+		return index, nil
+	}
+
+	var reference SymbolReference
+	switch {
+	case ref.pkgIdx == indexedPkgIdxBuiltin:
+		var err error
+		if reference, err = r.builtinReference(file, int(ref.symIdx)); err != nil {
+			return 0, err
+		}
+	case ref.pkgIdx < indexedPkgIdxSelf && int(ref.pkgIdx) < len(r.pkgPaths):
+		name, ok := r.refNames[ref]
+		if !ok {
+			// the names of the symbols referred only by the aux data (ex: the inlined functions) are not written.
+			name = fmt.Sprintf("<%s %d>", r.pkgPaths[ref.pkgIdx], ref.symIdx)
+		}
+		reference.Name = name
+	default:
+		return 0, fmt.Errorf("invalid symbol reference: %+v", ref)
+	}
+
+	file.SymbolReferences = append(file.SymbolReferences, reference)
+	r.refIndices[ref] = int64(len(file.SymbolReferences) - 1)
+	return r.refIndices[ref], nil
+}
+
+// builtinReference returns the reference to the builtin symbol. The list of builtin symbols differs by the go release,
+// so the error is returned if the list of the release the object file is built by is unknown.
+func (r *indexedReader) builtinReference(file *File, index int) (SymbolReference, error) {
+	minor, ok := file.Header.goMinorVersion()
+	list, listed := builtinLists[minor]
+	if !ok || !listed {
+		return SymbolReference{}, fmt.Errorf("the builtin symbols of %s are not known", file.Header.GoVersion)
+	}
+	if index < 0 || index >= len(list) {
+		return SymbolReference{}, fmt.Errorf("invalid builtin symbol index: %d", index)
+	}
+	return SymbolReference{Name: list[index].name, ABI: int64(list[index].abi)}, nil
+}
+
+func (r *indexedReader) readSymbol(file *File, i int) (Symbol, error) {
+	// the defined symbols are sequential regardless of the kind of the definition.
+	off := r.offsets[blkSymdef] + uint32(i*indexedSymSize)
+	symbol := Symbol{IDIndex: r.defRefIndex(i)}
+	symbol.Kind = file.Header.symKindOf(FormatIndexed, int64(r.obj[off+10]))
+	flag := r.obj[off+11]
+	symbol.DupOK = flag&indexedSymFlagDupok != 0
+	symbol.Local = flag&indexedSymFlagLocal != 0
+	symbol.Typelink = flag&indexedSymFlagTypelink != 0
+	symbol.Size = int64(r.uint32At(off + 13))
+
+	dataOff, dataEnd := r.indexAt(blkDataIdx, i), r.indexAt(blkDataIdx, i+1)
+	if dataOff > dataEnd || dataEnd > r.blockSize(blkData) {
+		return Symbol{}, fmt.Errorf("invalid data index of the symbol %d: %#x-%#x", i, dataOff, dataEnd)
+	}
+	symbol.DataAddr = DataAddr{Offset: int64(dataOff), Size: int64(dataEnd - dataOff)}
+
+	relocs, err := r.readRelocations(file, i)
+	if err != nil {
+		return Symbol{}, err
+	}
+	symbol.Relocations = relocs
+
+	if err := r.readAuxSymbols(file, i, &symbol, flag); err != nil {
+		return Symbol{}, err
+	}
+	return symbol, nil
+}
+
+// defRefIndex returns the index of SymbolReferences for the i-th defined symbol.
+func (r *indexedReader) defRefIndex(i int) int64 {
+	// the defined symbols are added to SymbolReferences first, in the same order.
+	return int64(i + 1)
+}
+
+func (r *indexedReader) readRelocations(file *File, i int) ([]Relocation, error) {
+	start, end := r.indexAt(blkRelocIdx, i), r.indexAt(blkRelocIdx, i+1)
+	if start > end || uint64(end)*uint64(r.relocSize) > uint64(r.blockSize(blkReloc)) {
+		return nil, fmt.Errorf("invalid relocation index of the symbol %d: %d-%d", i, start, end)
+	}
+	if err := checkLength("number of relocations", int64(end-start), r.maxRelocations); err != nil {
+		return nil, err
+	}
+
+	var relocs []Relocation
+	for j := start; j < end; j++ {
+		off := r.offsets[blkReloc] + j*uint32(r.relocSize)
+		reloc := Relocation{}
+		reloc.Offset = int64(int32(r.uint32At(off)))
+		reloc.Size = int64(r.obj[off+4])
+		off += 5
+		if r.relocSize == indexedRelocSizeUint8Type {
+			reloc.Type = file.Header.relocTypeOf(FormatIndexed, int64(r.obj[off]))
+			off++
+		} else {
+			reloc.Type = file.Header.relocTypeOf(FormatIndexed, int64(binary.LittleEndian.Uint16(r.obj[off:])))
+			off += 2
+		}
+		reloc.Add = int64(binary.LittleEndian.Uint64(r.obj[off:]))
+
+		index, err := r.refIndex(file, symRef{r.uint32At(off + 8), r.uint32At(off + 12)})
+		if err != nil {
+			return nil, err
+		}
+		reloc.IDIndex = index
+		relocs = append(relocs, reloc)
+	}
+	return relocs, nil
+}
+
+func (r *indexedReader) readAuxSymbols(file *File, i int, symbol *Symbol, flag byte) error {
+	start, end := r.indexAt(blkAuxIdx, i), r.indexAt(blkAuxIdx, i+1)
+	if start > end || uint64(end)*indexedAuxSize > uint64(r.blockSize(blkAux)) {
+		return fmt.Errorf("invalid aux index of the symbol %d: %d-%d", i, start, end)
+	}
+
+	var fields StextFields
+	var funcInfo *DataAddr
+	for j := start; j < end; j++ {
+		off := r.offsets[blkAux] + j*indexedAuxSize
+		ref := symRef{r.uint32At(off + 1), r.uint32At(off + 5)}
+		index, err := r.refIndex(file, ref)
+		if err != nil {
+			return err
+		}
+
+		switch r.obj[off] {
+		case auxGotype:
+			symbol.GoTypeIndex = index
+		case auxFuncInfo:
+			addr, err := r.auxDataAddr(ref)
+			if err != nil {
+				return err
+			}
+			funcInfo = &addr
+		case auxFuncdata:
+			fields.FuncDataIndex = append(fields.FuncDataIndex, index)
+		case auxPcsp, auxPcfile, auxPcline, auxPcinline, auxPcdata:
+			addr, err := r.auxDataAddr(ref)
+			if err != nil {
+				return err
+			}
+			switch r.obj[off] {
+			case auxPcsp:
+				fields.PCSP = addr
+			case auxPcfile:
+				fields.PCFile = addr
+			case auxPcline:
+				fields.PCLine = addr
+			case auxPcinline:
+				fields.PCInline = addr
+			default:
+				fields.PCData = append(fields.PCData, addr)
+			}
+		}
+	}
+
+	if funcInfo == nil {
+		return nil
+	}
+
+	fields.Leaf = flag&indexedSymFlagLeaf != 0
+	fields.NoSplit = flag&indexedSymFlagNoSplit != 0
+	fields.TypeMethod = flag&indexedSymFlagReflectMethod != 0
+	fields.SharedFunc = r.flags&indexedObjFlagShared != 0
+	b, err := r.readData(*funcInfo)
+	if err != nil {
+		return err
+	}
+	if err := r.readFuncInfo(file, b, &fields); err != nil {
+		return fmt.Errorf("failed to read the func info of the symbol %d: %v", i, err)
+	}
+	symbol.Func = &fields
+	return nil
+}
+
+// auxDataAddr returns the location of the aux symbol's data. The aux symbol must be defined in the object file.
+func (r *indexedReader) auxDataAddr(ref symRef) (DataAddr, error) {
+	index, ok := r.refIndices[ref]
+	if !ok || index < 1 || index > int64(r.numDefs) {
+		return DataAddr{}, fmt.Errorf("aux symbol is not defined: %+v", ref)
+	}
+
+	i := int(index - 1)
+	dataOff, dataEnd := r.indexAt(blkDataIdx, i), r.indexAt(blkDataIdx, i+1)
+	if dataOff > dataEnd || dataEnd > r.blockSize(blkData) {
+		return DataAddr{}, fmt.Errorf("invalid data index of the symbol %d: %#x-%#x", i, dataOff, dataEnd)
+	}
+	return DataAddr{Offset: int64(dataOff), Size: int64(dataEnd - dataOff)}, nil
+}
+
+// pcdataAddr returns the location of the go1.16 pcvalue table in the data block. The offsets are from the pcdata block.
+func (r *indexedReader) pcdataAddr(start, end uint32) (DataAddr, error) {
+	if start > end || end > r.blockSize(blkPcdata) {
+		return DataAddr{}, fmt.Errorf("invalid offset of the pcvalue table: %#x-%#x", start, end)
+	}
+	return DataAddr{Offset: int64(r.blockSize(blkData) + start), Size: int64(end - start)}, nil
+}
+
+// readFuncInfo reads the FuncInfo, which is serialized as the data of the aux symbol.
+func (r *indexedReader) readFuncInfo(file *File, b []byte, fields *StextFields) error {
+	readUint32 := func() (uint32, error) {
+		if len(b) < 4 {
+			return 0, errors.New("too short func info")
+		}
+		v := binary.LittleEndian.Uint32(b)
+		b = b[4:]
+		return v, nil
+	}
+
+	args, err := readUint32()
+	if err != nil {
+		return err
+	}
+	fields.Args = int64(args)
+	locals, err := readUint32()
+	if err != nil {
+		return err
+	}
+	fields.Frame = int64(locals)
+	// func id, func flag and padding
+	if _, err := readUint32(); err != nil {
+		return err
+	}
+
+	if r.version < indexedVersionPCDataAux {
+		// go1.16 has the offsets of the pcvalue tables in the pcdata block. Each table ends where the next one starts.
+		var offsets []uint32
+		for i := 0; i < 5; i++ {
+			offset, err := readUint32()
+			if err != nil {
+				return err
+			}
+			offsets = append(offsets, offset)
+		}
+		// the last value is the number of the pcdata tables. Their offsets and the end offset follow.
+		numPCData := offsets[4]
+		offsets = offsets[:4]
+		for i := uint32(0); i <= numPCData; i++ {
+			offset, err := readUint32()
+			if err != nil {
+				return err
+			}
+			offsets = append(offsets, offset)
+		}
+
+		var tables []DataAddr
+		for i := 0; i+1 < len(offsets); i++ {
+			addr, err := r.pcdataAddr(offsets[i], offsets[i+1])
+			if err != nil {
+				return err
+			}
+			tables = append(tables, addr)
+		}
+		fields.PCSP, fields.PCFile, fields.PCLine, fields.PCInline = tables[0], tables[1], tables[2], tables[3]
+		fields.PCData = tables[4:]
+	}
+
+	if r.version >= indexedVersionStartLine {
+		// start line
+		if _, err := readUint32(); err != nil {
+			return err
+		}
+	} else {
+		numFuncDataOffsets, err := readUint32()
+		if err != nil {
+			return err
+		}
+		for i := uint32(0); i < numFuncDataOffsets; i++ {
+			offset, err := readUint32()
+			if err != nil {
+				return err
+			}
+			fields.FuncDataOffset = append(fields.FuncDataOffset, int64(offset))
+		}
+	}
+	if r.version >= indexedVersionStartLine {
+		// the offsets are always 0 since go1.20
+		fields.FuncDataOffset = make([]int64, len(fields.FuncDataIndex))
+	}
+
+	numFiles, err := readUint32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < numFiles; i++ {
+		cuFileIndex, err := readUint32()
+		if err != nil {
+			return err
+		}
+		if int(cuFileIndex) >= len(r.fileRefIndices) {
+			return fmt.Errorf("invalid file index: %d", cuFileIndex)
+		}
+	}
+	// unlike the go19ld format, the values of the pcfile table are the indexes of the file block,
+	// not of the function's file list. So the whole file block is the symbol's FileIndex.
+	fields.FileIndex = append([]int64(nil), r.fileRefIndices...)
+
+	numInlTrees, err := readUint32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < numInlTrees; i++ {
+		var node [6]uint32 // parent, file, line, func (2 words) and parent pc
+		for j := range node {
+			if node[j], err = readUint32(); err != nil {
+				return err
+			}
+		}
+
+		call := InlinedCall{Parent: int64(int32(node[0])), Line: int64(int32(node[2]))}
+		if int(node[1]) >= len(r.fileRefIndices) {
+			return fmt.Errorf("invalid file index of the inlined call: %d", node[1])
+		}
+		call.FileIndex = r.fileRefIndices[node[1]]
+		call.File = file.SymbolReferences[call.FileIndex].Name[len(fileSymbolPrefix):]
+		if call.FuncIndex, err = r.refIndex(file, symRef{node[3], node[4]}); err != nil {
+			return err
+		}
+		call.Func = file.SymbolReferences[call.FuncIndex].Name
+		fields.InlineTree = append(fields.InlineTree, call)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package goobj
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// built by go1.27 with `-trimpath`
+var helloworldIndexedObjPath = filepath.Join("cmd", "readgoobj", "testdata", "helloworld_indexed.o")
+
+func TestParse_Indexed(t *testing.T) {
+	file := parseFileForTesting(t, helloworldIndexedObjPath)
+
+	if file.Header.GoVersion != "go1.27.1" {
+		t.Errorf("go version should be go1.27.1, but %s", file.Header.GoVersion)
+	}
+	if !reflect.DeepEqual([]string{"fmt"}, file.Imports) {
+		t.Errorf("imports should be [fmt], but %v", file.Imports)
+	}
+	if int64(len(file.DataBlock)) != file.Header.DataLength {
+		t.Errorf("data length should be %d, but %d", len(file.DataBlock), file.Header.DataLength)
+	}
+
+	mainFunc := file.Symbols[0]
+	if name := file.SymbolReferences[mainFunc.IDIndex].Name; name != "main.main" {
+		t.Fatalf("the 1st symbol should be main.main, but %s", name)
+	}
+	if mainFunc.Kind != STEXT || mainFunc.Size != 0x52 || mainFunc.DataAddr != (DataAddr{Size: 0x52, Offset: 0}) {
+		t.Errorf("invalid symbol: %+v", mainFunc)
+	}
+	if mainFunc.Func == nil || mainFunc.Func.Frame != 64 || len(mainFunc.Func.FuncDataIndex) != 3 {
+		t.Fatalf("invalid func: %+v", mainFunc.Func)
+	}
+
+	var relocTargets []string
+	for _, reloc := range mainFunc.Relocations {
+		relocTargets = append(relocTargets, file.SymbolReferences[reloc.IDIndex].Name)
+	}
+	expectedTargets := []string{"type:string", "type:*os.File", "type:string", "main..stmp_0", "os.Stdout",
+		"go:itab.*os.File,io.Writer", "fmt.Fprintln", "runtime.morestack_noctxt"}
+	if !reflect.DeepEqual(expectedTargets, relocTargets) {
+		t.Errorf("relocation targets should be %v, but %v", expectedTargets, relocTargets)
+	}
+
+	pcsp, err := file.PCSP(&mainFunc)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expectedPCSP := []PCValueRange{{0, 7, 0}, {7, 14, 8}, {14, 73, 64}, {73, 74, 8}, {74, 82, 0}}
+	if !reflect.DeepEqual(expectedPCSP, pcsp) {
+		t.Errorf("pcsp should be %v, but %v", expectedPCSP, pcsp)
+	}
+
+	filename, line, err := file.LineForPC(&mainFunc, 0x26)
+	if err != nil || filename != "$GOROOT/src/fmt/print.go" || line != 307 {
+		t.Errorf("invalid line: %s:%d, %v", filename, line, err)
+	}
+
+	if len(mainFunc.Func.InlineTree) != 1 || mainFunc.Func.InlineTree[0].File != "helloworld.go" || mainFunc.Func.InlineTree[0].Line != 8 {
+		t.Errorf("invalid inline tree: %+v", mainFunc.Func.InlineTree)
+	}
+}
+
+func TestParse_IndexedStaticSymbol(t *testing.T) {
+	file := parseFileForTesting(t, helloworldIndexedObjPath)
+	for _, symbol := range file.Symbols {
+		ref := file.SymbolReferences[symbol.IDIndex]
+		if ref.Name == "main..stmp_0" && ref.Version != 1 {
+			t.Errorf("the version of the static symbol should be 1, but %d", ref.Version)
+		}
+	}
+}
+
+func TestParseBytes_IndexedTruncated(t *testing.T) {
+	obj := []byte("go object linux amd64 go1.27.1\n!\n\x00go120ld" + "\x00\x00\x00\x00")
+	if _, err := ParseBytes(obj); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestIsIndexedMagic(t *testing.T) {
+	for i, testData := range []struct {
+		in       string
+		expected bool
+	}{
+		{in: "\x00go116ld", expected: true},
+		{in: "\x00go120ld", expected: true},
+		{in: "\x00go115ld", expected: false},
+		{in: "\x00\x00go19ld", expected: false},
+		{in: "\x00go1+1ld", expected: false},
+		{in: "\x00go120l", expected: false},
+	} {
+		if actual := isIndexedMagic([]byte(testData.in)); actual != testData.expected {
+			t.Errorf("[%d] should be %v, but %v", i, testData.expected, actual)
+		}
+	}
+}
+
+func TestIndexedReader_builtinReference(t *testing.T) {
+	for i, testData := range []struct {
+		goVersion string
+		index     int
+		expected  SymbolReference
+	}{
+		{goVersion: "go1.27.1", index: 0, expected: SymbolReference{Name: "runtime.newobject", ABI: 1}},
+		{goVersion: "go1.20.14", index: 65, expected: SymbolReference{Name: "runtime.convT2E", ABI: 1}},
+		{goVersion: "go1.21.0", index: 65, expected: SymbolReference{Name: "runtime.convTstring", ABI: 1}},
+		{goVersion: "go1.26rc1", index: 224, expected: SymbolReference{Name: "runtime.gcWriteBarrier", ABI: 1}},
+	} {
+		file := &File{Header: Header{GoVersion: testData.goVersion}}
+		actual, err := (&indexedReader{}).builtinReference(file, testData.index)
+		if err != nil || actual != testData.expected {
+			t.Errorf("[%d] the reference should be %+v, but %+v (%v)", i, testData.expected, actual, err)
+		}
+	}
+}
+
+func TestIndexedReader_builtinReference_Unknown(t *testing.T) {
+	for i, testData := range []struct {
+		goVersion string
+		index     int
+	}{
+		{goVersion: "go1.27.1", index: 1000},
+		{goVersion: "go1.16", index: 0},
+		{goVersion: "go1.19.13", index: 0},
+		{goVersion: "devel", index: 0},
+	} {
+		file := &File{Header: Header{GoVersion: testData.goVersion}}
+		if _, err := (&indexedReader{}).builtinReference(file, testData.index); err == nil {
+			t.Errorf("[%d] error should not be nil", i)
+		}
+	}
+}
+
+func TestIndexedReader_readFuncInfo_Go116(t *testing.T) {
+	r := &indexedReader{version: 16, offsets: make([]uint32, blkPcdata+2), fileRefIndices: []int64{5}}
+	r.offsets[blkPcdata], r.offsets[blkPcdata+1] = 10, 30
+	// args, locals, func id, pcsp, pcfile, pcline, pcinline, pcdata (1 table), pcdata end,
+	// funcdata offsets (none), files (1 file) and inline tree (none)
+	funcInfo := uint32sForTesting(8, 16, 0, 0, 4, 8, 12, 1, 14, 20, 0, 1, 0, 0)
+
+	var fields StextFields
+	if err := r.readFuncInfo(&File{}, funcInfo, &fields); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := StextFields{
+		Args: 8, Frame: 16,
+		PCSP: DataAddr{Offset: 10, Size: 4}, PCFile: DataAddr{Offset: 14, Size: 4},
+		PCLine: DataAddr{Offset: 18, Size: 4}, PCInline: DataAddr{Offset: 22, Size: 2},
+		PCData:    []DataAddr{{Offset: 24, Size: 6}},
+		FileIndex: []int64{5},
+	}
+	if !reflect.DeepEqual(expected, fields) {
+		t.Errorf("the fields should be %+v, but %+v", expected, fields)
+	}
+}
+
+func TestIndexedReader_readFuncInfo_Go116OutOfPCData(t *testing.T) {
+	r := &indexedReader{version: 16, offsets: make([]uint32, blkPcdata+2)}
+	r.offsets[blkPcdata], r.offsets[blkPcdata+1] = 10, 30
+	funcInfo := uint32sForTesting(8, 16, 0, 0, 4, 8, 12, 0, 100, 0, 0, 0)
+
+	if err := r.readFuncInfo(&File{}, funcInfo, &StextFields{}); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func uint32sForTesting(values ...uint32) []byte {
+	b := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(b[4*i:], v)
+	}
+	return b
+}
@@ -0,0 +1,380 @@
+package goobj
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The numbering of the symbol kinds and the relocation types changes between the go releases.
+// The parser converts the numbers in the object file to SymKind and RelocType using the tables below,
+// which are chosen by the format and the go version in the header line.
+
+// taken from go1.11 to go1.15 cmd/internal/objabi
+var go111SymKinds = []SymKind{
+	Sxxx, STEXT, SRODATA, SNOPTRDATA, SDATA, SBSS, SNOPTRBSS, STLSBSS, SDWARFINFO, SDWARFRANGE, SDWARFLOC,
+	SDWARFMISC, SABIALIAS, SLIBFUZZER_EXTRA_COUNTER,
+}
+
+// taken from go1.16 cmd/internal/objabi
+var go116SymKinds = []SymKind{
+	Sxxx, STEXT, SRODATA, SNOPTRDATA, SDATA, SBSS, SNOPTRBSS, STLSBSS,
+	SDWARFCUINFO, SDWARFCONST, SDWARFFCN, SDWARFABSFCN, SDWARFTYPE, SDWARFVAR, SDWARFRANGE, SDWARFLOC, SDWARFLINES,
+	SABIALIAS, SLIBFUZZER_EXTRA_COUNTER,
+}
+
+// taken from go1.17 to go1.23 cmd/internal/objabi
+var go117SymKinds = []SymKind{
+	Sxxx, STEXT, SRODATA, SNOPTRDATA, SDATA, SBSS, SNOPTRBSS, STLSBSS,
+	SDWARFCUINFO, SDWARFCONST, SDWARFFCN, SDWARFABSFCN, SDWARFTYPE, SDWARFVAR, SDWARFRANGE, SDWARFLOC, SDWARFLINES,
+	SLIBFUZZER_EXTRA_COUNTER, SCOVERAGE_COUNTER, SCOVERAGE_AUXVAR, SSEHUNWINDINFO,
+}
+
+// taken from go1.24 cmd/internal/objabi
+var go124SymKinds = []SymKind{
+	Sxxx, STEXT, STEXTFIPS, SRODATA, SRODATAFIPS, SNOPTRDATA, SNOPTRDATAFIPS, SDATA, SDATAFIPS, SBSS, SNOPTRBSS, STLSBSS,
+	SDWARFCUINFO, SDWARFCONST, SDWARFFCN, SDWARFABSFCN, SDWARFTYPE, SDWARFVAR, SDWARFRANGE, SDWARFLOC, SDWARFLINES,
+	SLIBFUZZER_EXTRA_COUNTER, SCOVERAGE_COUNTER, SCOVERAGE_AUXVAR, SSEHUNWINDINFO,
+}
+
+// taken from go1.25 to go1.27 cmd/internal/objabi
+var go125SymKinds = []SymKind{
+	Sxxx, STEXT, STEXTFIPS, SRODATA, SRODATAFIPS, SNOPTRDATA, SNOPTRDATAFIPS, SDATA, SDATAFIPS, SBSS, SNOPTRBSS, STLSBSS,
+	SDWARFCUINFO, SDWARFCONST, SDWARFFCN, SDWARFABSFCN, SDWARFTYPE, SDWARFVAR, SDWARFRANGE, SDWARFLOC, SDWARFLINES,
+	SDWARFADDR, SLIBFUZZER_EXTRA_COUNTER, SCOVERAGE_COUNTER, SCOVERAGE_AUXVAR, SSEHUNWINDINFO,
+}
+
+// The relocation types are converted only for the releases whose numbering is checked against cmd/internal/objabi,
+// that is, go1.10 and go1.20 or later. Those of go1.10 are same as RelocType. The relocation types of the other
+// releases are kept as the numbers in the object file. See RelocType.Unconverted.
+
+// taken from go1.20 cmd/internal/objabi. Some types are renamed later and converted to the new names:
+// R_USEGENERICIFACEMETHOD to R_USENAMEDMETHOD, R_RISCV_CALL(_TRAMP) to R_RISCV_JAL(_TRAMP),
+// R_ADDRLOONG64(U) to R_LOONG64_ADDR_LO(HI) and R_ADDRLOONG64TLS(U) to R_LOONG64_TLS_LE_LO(HI).
+var go120RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_DWARFFILEREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT,
+	R_ARM64_PCREL, R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64,
+	R_ARM64_LDST8, R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE,
+	R_POWER_TLS, R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT,
+	R_ADDRPOWER_GOT_PCREL34, R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34,
+	R_ADDRPOWER_PCREL34, R_RISCV_JAL, R_RISCV_JAL_TRAMP, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE,
+	R_RISCV_TLS_IE_ITYPE, R_RISCV_TLS_IE_STYPE, R_PCRELDBL, R_LOONG64_ADDR_LO, R_LOONG64_ADDR_HI,
+	R_LOONG64_TLS_LE_LO, R_LOONG64_TLS_LE_HI, R_CALLLOONG64, R_JMPLOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF,
+	R_WASMIMPORT, R_XCOFFREF,
+}
+
+// taken from go1.21 cmd/internal/objabi. R_LOONG64_TLS_IE_PCREL_HI is converted to R_LOONG64_TLS_IE_HI.
+var go121RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_DWARFFILEREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT,
+	R_ARM64_PCREL, R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64,
+	R_ARM64_LDST8, R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE,
+	R_POWER_TLS, R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT,
+	R_ADDRPOWER_GOT_PCREL34, R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34,
+	R_ADDRPOWER_PCREL34, R_RISCV_JAL, R_RISCV_JAL_TRAMP, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE,
+	R_RISCV_TLS_IE_ITYPE, R_RISCV_TLS_IE_STYPE, R_PCRELDBL, R_LOONG64_ADDR_LO, R_LOONG64_ADDR_HI,
+	R_LOONG64_TLS_LE_LO, R_LOONG64_TLS_LE_HI, R_CALLLOONG64, R_LOONG64_TLS_IE_HI, R_LOONG64_TLS_IE_LO, R_JMPLOONG64,
+	R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF, R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF, R_INITORDER,
+}
+
+// taken from go1.22 cmd/internal/objabi
+var go122RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_DWARFFILEREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT,
+	R_ARM64_PCREL, R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64,
+	R_ARM64_LDST8, R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE,
+	R_POWER_TLS, R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT,
+	R_ADDRPOWER_GOT_PCREL34, R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34,
+	R_ADDRPOWER_PCREL34, R_RISCV_JAL, R_RISCV_JAL_TRAMP, R_RISCV_CALL, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE,
+	R_RISCV_TLS_IE, R_RISCV_TLS_LE, R_RISCV_GOT_HI20, R_RISCV_PCREL_HI20, R_RISCV_PCREL_LO12_I,
+	R_RISCV_PCREL_LO12_S, R_RISCV_BRANCH, R_RISCV_RVC_BRANCH, R_RISCV_RVC_JUMP, R_PCRELDBL, R_LOONG64_ADDR_LO,
+	R_LOONG64_ADDR_HI, R_LOONG64_TLS_LE_LO, R_LOONG64_TLS_LE_HI, R_CALLLOONG64, R_LOONG64_TLS_IE_HI,
+	R_LOONG64_TLS_IE_LO, R_LOONG64_GOT_HI, R_LOONG64_GOT_LO, R_JMPLOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF,
+	R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF, R_INITORDER,
+}
+
+// taken from go1.23 cmd/internal/objabi
+var go123RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_DWARFFILEREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT,
+	R_ARM64_PCREL, R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64,
+	R_ARM64_LDST8, R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE,
+	R_POWER_TLS, R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT,
+	R_ADDRPOWER_GOT_PCREL34, R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34,
+	R_ADDRPOWER_PCREL34, R_RISCV_JAL, R_RISCV_JAL_TRAMP, R_RISCV_CALL, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE,
+	R_RISCV_TLS_IE, R_RISCV_TLS_LE, R_RISCV_GOT_HI20, R_RISCV_PCREL_HI20, R_RISCV_PCREL_LO12_I,
+	R_RISCV_PCREL_LO12_S, R_RISCV_BRANCH, R_RISCV_RVC_BRANCH, R_RISCV_RVC_JUMP, R_PCRELDBL, R_LOONG64_ADDR_HI,
+	R_LOONG64_ADDR_LO, R_LOONG64_TLS_LE_HI, R_LOONG64_TLS_LE_LO, R_CALLLOONG64, R_LOONG64_TLS_IE_HI,
+	R_LOONG64_TLS_IE_LO, R_LOONG64_GOT_HI, R_LOONG64_GOT_LO, R_JMPLOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF,
+	R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF, R_INITORDER,
+}
+
+// taken from go1.24 cmd/internal/objabi
+var go124RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_DWARFFILEREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT,
+	R_ARM64_PCREL, R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64,
+	R_ARM64_LDST8, R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE,
+	R_POWER_TLS, R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT,
+	R_ADDRPOWER_GOT_PCREL34, R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34,
+	R_ADDRPOWER_PCREL34, R_RISCV_JAL, R_RISCV_JAL_TRAMP, R_RISCV_CALL, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE,
+	R_RISCV_TLS_IE, R_RISCV_TLS_LE, R_RISCV_GOT_HI20, R_RISCV_PCREL_HI20, R_RISCV_PCREL_LO12_I,
+	R_RISCV_PCREL_LO12_S, R_RISCV_BRANCH, R_RISCV_RVC_BRANCH, R_RISCV_RVC_JUMP, R_PCRELDBL, R_LOONG64_ADDR_HI,
+	R_LOONG64_ADDR_LO, R_LOONG64_TLS_LE_HI, R_LOONG64_TLS_LE_LO, R_CALLLOONG64, R_LOONG64_TLS_IE_HI,
+	R_LOONG64_TLS_IE_LO, R_LOONG64_GOT_HI, R_LOONG64_GOT_LO, R_LOONG64_ADD64, R_LOONG64_SUB64, R_JMP16LOONG64,
+	R_JMP21LOONG64, R_JMPLOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF, R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF,
+	R_INITORDER,
+}
+
+// taken from go1.25 cmd/internal/objabi
+var go125RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT, R_ARM64_PCREL,
+	R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64, R_ARM64_LDST8,
+	R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE, R_POWER_TLS,
+	R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT, R_ADDRPOWER_GOT_PCREL34,
+	R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34, R_ADDRPOWER_PCREL34, R_RISCV_JAL,
+	R_RISCV_JAL_TRAMP, R_RISCV_CALL, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE, R_RISCV_TLS_IE, R_RISCV_TLS_LE,
+	R_RISCV_GOT_HI20, R_RISCV_GOT_PCREL_ITYPE, R_RISCV_PCREL_HI20, R_RISCV_PCREL_LO12_I, R_RISCV_PCREL_LO12_S,
+	R_RISCV_BRANCH, R_RISCV_RVC_BRANCH, R_RISCV_RVC_JUMP, R_PCRELDBL, R_LOONG64_ADDR_HI, R_LOONG64_ADDR_LO,
+	R_LOONG64_TLS_LE_HI, R_LOONG64_TLS_LE_LO, R_CALLLOONG64, R_LOONG64_TLS_IE_HI, R_LOONG64_TLS_IE_LO,
+	R_LOONG64_GOT_HI, R_LOONG64_GOT_LO, R_LOONG64_ADD64, R_LOONG64_SUB64, R_JMP16LOONG64, R_JMP21LOONG64,
+	R_JMPLOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF, R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF, R_INITORDER,
+	R_DWTXTADDR_U1, R_DWTXTADDR_U2, R_DWTXTADDR_U3, R_DWTXTADDR_U4,
+}
+
+// taken from go1.26 cmd/internal/objabi
+var go126RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT, R_ARM64_PCREL,
+	R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64, R_ARM64_LDST8,
+	R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE, R_POWER_TLS,
+	R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT, R_ADDRPOWER_GOT_PCREL34,
+	R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34, R_ADDRPOWER_PCREL34, R_RISCV_JAL,
+	R_RISCV_JAL_TRAMP, R_RISCV_CALL, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE, R_RISCV_TLS_IE, R_RISCV_TLS_LE,
+	R_RISCV_GOT_HI20, R_RISCV_GOT_PCREL_ITYPE, R_RISCV_PCREL_HI20, R_RISCV_PCREL_LO12_I, R_RISCV_PCREL_LO12_S,
+	R_RISCV_BRANCH, R_RISCV_ADD32, R_RISCV_SUB32, R_RISCV_RVC_BRANCH, R_RISCV_RVC_JUMP, R_PCRELDBL,
+	R_LOONG64_ADDR_HI, R_LOONG64_ADDR_LO, R_LOONG64_ADDR_PCREL20_S2, R_LOONG64_TLS_LE_HI, R_LOONG64_TLS_LE_LO,
+	R_CALLLOONG64, R_LOONG64_CALL36, R_LOONG64_TLS_IE_HI, R_LOONG64_TLS_IE_LO, R_LOONG64_GOT_HI, R_LOONG64_GOT_LO,
+	R_LOONG64_ADD64, R_LOONG64_SUB64, R_JMP16LOONG64, R_JMP21LOONG64, R_JMPLOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS,
+	R_ADDRCUOFF, R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF, R_INITORDER, R_DWTXTADDR_U1, R_DWTXTADDR_U2,
+	R_DWTXTADDR_U3, R_DWTXTADDR_U4,
+}
+
+// taken from go1.27 cmd/internal/objabi
+var go127RelocTypes = []RelocType{
+	0, R_ADDR, R_ADDRPOWER, R_ADDRARM64, R_ADDRMIPS, R_ADDROFF, R_SIZE, R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND,
+	R_CALLPOWER, R_CALLMIPS, R_CONST, R_PCREL, R_TLS_LE, R_TLS_IE, R_GOTOFF, R_PLT0, R_PLT1, R_PLT2, R_USEFIELD,
+	R_USETYPE, R_USEIFACE, R_USEIFACEMETHOD, R_USENAMEDMETHOD, R_METHODOFF, R_KEEP, R_POWER_TOC, R_GOTPCREL,
+	R_JMPMIPS, R_DWARFSECREF, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_ARM64_GOTPCREL, R_ARM64_GOT, R_ARM64_PCREL,
+	R_ARM64_PCREL_LDST8, R_ARM64_PCREL_LDST16, R_ARM64_PCREL_LDST32, R_ARM64_PCREL_LDST64, R_ARM64_LDST8,
+	R_ARM64_LDST16, R_ARM64_LDST32, R_ARM64_LDST64, R_ARM64_LDST128, R_POWER_TLS_LE, R_POWER_TLS_IE, R_POWER_TLS,
+	R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRPOWER_DS, R_ADDRPOWER_GOT, R_ADDRPOWER_GOT_PCREL34,
+	R_ADDRPOWER_PCREL, R_ADDRPOWER_TOCREL, R_ADDRPOWER_TOCREL_DS, R_ADDRPOWER_D34, R_ADDRPOWER_PCREL34, R_RISCV_JAL,
+	R_RISCV_JAL_TRAMP, R_RISCV_CALL, R_RISCV_PCREL_ITYPE, R_RISCV_PCREL_STYPE, R_RISCV_TLS_IE, R_RISCV_TLS_LE,
+	R_RISCV_GOT_HI20, R_RISCV_GOT_PCREL_ITYPE, R_RISCV_PCREL_HI20, R_RISCV_PCREL_LO12_I, R_RISCV_PCREL_LO12_S,
+	R_RISCV_BRANCH, R_RISCV_ADD32, R_RISCV_SUB32, R_RISCV_RVC_BRANCH, R_RISCV_RVC_JUMP, R_PCRELDBL,
+	R_LOONG64_ADDR_HI, R_LOONG64_ADDR_LO, R_LOONG64_ADDR64_HI, R_LOONG64_ADDR64_LO, R_LOONG64_ADDR_PCREL20_S2,
+	R_LOONG64_TLS_LE_HI, R_LOONG64_TLS_LE_LO, R_CALLLOONG64, R_LOONG64_CALL36, R_LOONG64_TLS_IE_HI,
+	R_LOONG64_TLS_IE_LO, R_LOONG64_GOT_HI, R_LOONG64_GOT_LO, R_LOONG64_GOT64_HI, R_LOONG64_GOT64_LO,
+	R_LOONG64_ADD64, R_LOONG64_SUB64, R_JMP16LOONG64, R_JMP21LOONG64, R_ADDRMIPSU, R_ADDRMIPSTLS, R_ADDRCUOFF,
+	R_WASMIMPORT, R_XCOFFREF, R_PEIMAGEOFF, R_INITORDER, R_DWTXTADDR_U1, R_DWTXTADDR_U2, R_DWTXTADDR_U3,
+	R_DWTXTADDR_U4,
+}
+
+// the flag of the weak relocation since go1.17
+const relocTypeWeak = 1 << 15
+
+// the flag of the relocation type which is not converted. See RelocType.Unconverted.
+const relocTypeUnconverted RelocType = 1 << 30
+
+// symKindTable returns the table to convert the symbol kinds in the object file. Nil if no conversion is necessary.
+func (h Header) symKindTable(format Format) []SymKind {
+	minor, ok := h.goMinorVersion()
+	switch format {
+	case FormatGo19:
+		if ok && minor >= 11 {
+			return go111SymKinds
+		}
+	case FormatIndexed:
+		switch {
+		case !ok || minor >= 25:
+			return go125SymKinds
+		case minor >= 24:
+			return go124SymKinds
+		case minor >= 17:
+			return go117SymKinds
+		default:
+			return go116SymKinds
+		}
+	}
+	return nil
+}
+
+// symKindOf converts the symbol kind in the object file to SymKind.
+func (h Header) symKindOf(format Format, kind int64) SymKind {
+	switch format {
+	case FormatGo13:
+		return legacySymKind(go13SymKinds, kind)
+	case FormatGo17:
+		return legacySymKind(go17SymKinds, kind)
+	}
+
+	table := h.symKindTable(format)
+	if table == nil {
+		return SymKind(kind)
+	}
+	if kind < 0 || kind >= int64(len(table)) {
+		return Sxxx
+	}
+	return table[kind]
+}
+
+// relocTypeTable returns the table to convert the relocation types in the object file. Nil if no conversion is necessary.
+// The 2nd result is false if the numbering of the relocation types of the go release is not known.
+func (h Header) relocTypeTable(format Format) ([]RelocType, bool) {
+	minor, ok := h.goMinorVersion()
+	switch format {
+	case FormatGo13, FormatGo17:
+		// the numbering of go1.5 to go1.8 is not known.
+	case FormatIndexed:
+		switch {
+		case !ok || minor >= 27:
+			return go127RelocTypes, true
+		case minor >= 26:
+			return go126RelocTypes, true
+		case minor >= 25:
+			return go125RelocTypes, true
+		case minor >= 24:
+			return go124RelocTypes, true
+		case minor >= 23:
+			return go123RelocTypes, true
+		case minor >= 22:
+			return go122RelocTypes, true
+		case minor >= 21:
+			return go121RelocTypes, true
+		case minor >= 20:
+			return go120RelocTypes, true
+		}
+	default:
+		if !ok || minor == 10 {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// relocTypeOf converts the relocation type in the object file to RelocType.
+func (h Header) relocTypeOf(format Format, relocType int64) RelocType {
+	table, known := h.relocTypeTable(format)
+	if !known {
+		if relocType < 0 || relocType >= int64(relocTypeUnconverted) {
+			return 0
+		}
+		return relocTypeUnconverted | RelocType(relocType)
+	}
+	if table == nil {
+		return RelocType(relocType)
+	}
+
+	weak := format == FormatIndexed && relocType&relocTypeWeak != 0
+	if weak {
+		relocType &^= relocTypeWeak
+	}
+	if relocType < 0 || relocType >= int64(len(table)) {
+		return 0
+	}
+
+	converted := table[relocType]
+	if weak {
+		switch converted {
+		case R_ADDR:
+			return R_WEAKADDR
+		case R_ADDROFF:
+			return R_WEAKADDROFF
+		}
+	}
+	return converted
+}
+
+// Unconverted returns the relocation type in the object file and true if the type is not converted to the known type,
+// because the numbering of the relocation types of the go release which built the object file is not known.
+func (relocType RelocType) Unconverted() (int64, bool) {
+	if relocType&relocTypeUnconverted == 0 {
+		return 0, false
+	}
+	return int64(relocType &^ relocTypeUnconverted), true
+}
+
+// goMinorVersion returns the minor version of the go release in the header line, like 10 for go1.10.1.
+func (h Header) goMinorVersion() (int, bool) {
+	if !strings.HasPrefix(h.GoVersion, "go1.") {
+		return 0, false
+	}
+
+	version := strings.TrimPrefix(h.GoVersion, "go1.")
+	if i := strings.IndexAny(version, ".-abcdefghijklmnopqrstuvwxyz"); i >= 0 {
+		version = version[:i]
+	}
+	minor, err := strconv.Atoi(version)
+	return minor, err == nil
+}
+
+// IsDWARF returns true if the symbol holds the debugging information.
+func (kind SymKind) IsDWARF() bool {
+	switch kind {
+	case SDWARFINFO, SDWARFRANGE, SDWARFLOC, SDWARFMISC, SDWARFCUINFO, SDWARFCONST, SDWARFFCN, SDWARFABSFCN,
+		SDWARFTYPE, SDWARFVAR, SDWARFLINES, SDWARFADDR:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsCall returns true if the relocation is for the call instruction.
+func (relocType RelocType) IsCall() bool {
+	switch relocType {
+	case R_CALL, R_CALLARM, R_CALLARM64, R_CALLIND, R_CALLPOWER, R_CALLMIPS, R_CALLLOONG64,
+		R_RISCV_CALL, R_RISCV_JAL, R_RISCV_JAL_TRAMP, R_LOONG64_CALL36:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTLS returns true if the relocation refers to the thread-local storage.
+func (relocType RelocType) IsTLS() bool {
+	switch relocType {
+	case R_TLS_LE, R_TLS_IE, R_ARM64_TLS_LE, R_ARM64_TLS_IE, R_POWER_TLS_LE, R_POWER_TLS_IE, R_POWER_TLS,
+		R_POWER_TLS_IE_PCREL34, R_POWER_TLS_LE_TPREL34, R_ADDRMIPSTLS, R_RISCV_TLS_IE, R_RISCV_TLS_LE,
+		R_RISCV_TLS_IE_ITYPE, R_RISCV_TLS_IE_STYPE,
+		R_LOONG64_TLS_LE_HI, R_LOONG64_TLS_LE_LO, R_LOONG64_TLS_IE_HI, R_LOONG64_TLS_IE_LO:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDWARF returns true if the relocation is used by the debugging information.
+func (relocType RelocType) IsDWARF() bool {
+	switch relocType {
+	case R_DWARFSECREF, R_DWARFFILEREF, R_ADDRCUOFF, R_DWTXTADDR_U1, R_DWTXTADDR_U2, R_DWTXTADDR_U3, R_DWTXTADDR_U4:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,167 @@
+package goobj
+
+import "testing"
+
+func TestHeader_symKindOf(t *testing.T) {
+	for i, testData := range []struct {
+		goVersion string
+		format    Format
+		in        int64
+		expected  SymKind
+	}{
+		{goVersion: "go1.10", format: FormatGo19, in: 8, expected: SDWARFINFO},
+		{goVersion: "", format: FormatGo19, in: 10, expected: SDWARFLOC},
+		{goVersion: "go1.12.5", format: FormatGo19, in: 12, expected: SABIALIAS},
+		{goVersion: "go1.16", format: FormatIndexed, in: 16, expected: SDWARFLINES},
+		{goVersion: "go1.20.1", format: FormatIndexed, in: 18, expected: SCOVERAGE_COUNTER},
+		{goVersion: "go1.24rc1", format: FormatIndexed, in: 3, expected: SRODATA},
+		{goVersion: "go1.27.1", format: FormatIndexed, in: 21, expected: SDWARFADDR},
+		{goVersion: "", format: FormatIndexed, in: 2, expected: STEXTFIPS},
+		{goVersion: "go1.27.1", format: FormatIndexed, in: 100, expected: Sxxx},
+		{goVersion: "go1.8", format: FormatGo17, in: 30, expected: SDATA},
+	} {
+		header := Header{GoVersion: testData.goVersion}
+		if actual := header.symKindOf(testData.format, testData.in); actual != testData.expected {
+			t.Errorf("[%d] kind should be %s, but %s", i, testData.expected, actual)
+		}
+	}
+}
+
+func TestHeader_relocTypeOf(t *testing.T) {
+	for i, testData := range []struct {
+		goVersion string
+		format    Format
+		in        int64
+		expected  RelocType
+	}{
+		{goVersion: "go1.10", format: FormatGo19, in: 6, expected: R_WEAKADDROFF},
+		{goVersion: "go1.10", format: FormatGo19, in: 14, expected: R_CONST},
+		{goVersion: "", format: FormatGo19, in: 14, expected: R_CONST},
+		{goVersion: "go1.20.14", format: FormatIndexed, in: 25, expected: R_USENAMEDMETHOD},
+		{goVersion: "go1.22.0", format: FormatIndexed, in: 60, expected: R_RISCV_JAL},
+		{goVersion: "go1.27.1", format: FormatIndexed, in: 6, expected: R_SIZE},
+		{goVersion: "go1.27.1", format: FormatIndexed, in: relocTypeWeak | 1, expected: R_WEAKADDR},
+		{goVersion: "go1.27.1", format: FormatIndexed, in: relocTypeWeak | 5, expected: R_WEAKADDROFF},
+		{goVersion: "go1.27.1", format: FormatIndexed, in: 1000, expected: 0},
+		{goVersion: "devel", format: FormatIndexed, in: 98, expected: R_ADDRCUOFF},
+		// the numbering of the releases below is not known
+		{goVersion: "go1.6", format: FormatGo13, in: 6, expected: relocTypeUnconverted | 6},
+		{goVersion: "go1.8", format: FormatGo17, in: 5, expected: relocTypeUnconverted | 5},
+		{goVersion: "go1.12", format: FormatGo19, in: 14, expected: relocTypeUnconverted | 14},
+		{goVersion: "go1.19", format: FormatIndexed, in: relocTypeWeak | 1, expected: relocTypeUnconverted | relocTypeWeak | 1},
+		{goVersion: "go1.16", format: FormatIndexed, in: -1, expected: 0},
+	} {
+		header := Header{GoVersion: testData.goVersion}
+		if actual := header.relocTypeOf(testData.format, testData.in); actual != testData.expected {
+			t.Errorf("[%d] type should be %s, but %s", i, testData.expected, actual)
+		}
+	}
+}
+
+// The relocation types below are taken from cmd/internal/objabi/reloctype.go of each release.
+func TestHeader_relocTypeOf_Releases(t *testing.T) {
+	for _, testData := range []struct {
+		goVersion                         string
+		format                            Format
+		call, pcrel, tlsLE, methodOff, cu int64
+	}{
+		{goVersion: "go1.10", format: FormatGo19, call: 8, pcrel: 15, tlsLE: 16, methodOff: 24, cu: 44},
+		{goVersion: "go1.20", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 75},
+		{goVersion: "go1.21", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 77},
+		{goVersion: "go1.22", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 87},
+		{goVersion: "go1.23", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 87},
+		{goVersion: "go1.24", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 91},
+		{goVersion: "go1.25", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 91},
+		{goVersion: "go1.26", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 95},
+		{goVersion: "go1.27", format: FormatIndexed, call: 7, pcrel: 14, tlsLE: 15, methodOff: 26, cu: 98},
+	} {
+		header := Header{GoVersion: testData.goVersion}
+		for _, pair := range []struct {
+			in       int64
+			expected RelocType
+		}{
+			{testData.call, R_CALL}, {testData.pcrel, R_PCREL}, {testData.tlsLE, R_TLS_LE},
+			{testData.methodOff, R_METHODOFF}, {testData.cu, R_ADDRCUOFF},
+		} {
+			if actual := header.relocTypeOf(testData.format, pair.in); actual != pair.expected {
+				t.Errorf("[%s] type %d should be %s, but %s", testData.goVersion, pair.in, pair.expected, actual)
+			}
+		}
+	}
+}
+
+func TestRelocType_Unconverted(t *testing.T) {
+	relocType := Header{GoVersion: "go1.12"}.relocTypeOf(FormatGo19, 8)
+	if raw, ok := relocType.Unconverted(); !ok || raw != 8 {
+		t.Errorf("the type should be unconverted 8, but %d, %v", raw, ok)
+	}
+	if relocType.String() != "RelocType(8)" {
+		t.Errorf("the name should be the number, but %s", relocType)
+	}
+	if _, ok := R_CALL.Unconverted(); ok {
+		t.Errorf("R_CALL should be converted")
+	}
+}
+
+func TestHeader_goMinorVersion(t *testing.T) {
+	for i, testData := range []struct {
+		in         string
+		expected   int
+		expectedOK bool
+	}{
+		{in: "go1.10", expected: 10, expectedOK: true},
+		{in: "go1.27.1", expected: 27, expectedOK: true},
+		{in: "go1.24rc1", expected: 24, expectedOK: true},
+		{in: "devel", expectedOK: false},
+		{in: "", expectedOK: false},
+	} {
+		actual, ok := Header{GoVersion: testData.in}.goMinorVersion()
+		if actual != testData.expected || ok != testData.expectedOK {
+			t.Errorf("[%d] should be %d, %v, but %d, %v", i, testData.expected, testData.expectedOK, actual, ok)
+		}
+	}
+}
+
+func TestSymKind_IsDWARF(t *testing.T) {
+	if !SDWARFINFO.IsDWARF() || !SDWARFLINES.IsDWARF() {
+		t.Errorf("should be dwarf")
+	}
+	if STEXT.IsDWARF() || SRODATA.IsDWARF() {
+		t.Errorf("should not be dwarf")
+	}
+}
+
+func TestRelocType_IsCall(t *testing.T) {
+	if !R_CALL.IsCall() || !R_RISCV_CALL.IsCall() || !R_RISCV_JAL.IsCall() {
+		t.Errorf("should be call")
+	}
+	if R_PCREL.IsCall() {
+		t.Errorf("should not be call")
+	}
+}
+
+func TestRelocType_IsTLS(t *testing.T) {
+	if !R_TLS_LE.IsTLS() || !R_LOONG64_TLS_IE_HI.IsTLS() {
+		t.Errorf("should be tls")
+	}
+	if R_ADDR.IsTLS() {
+		t.Errorf("should not be tls")
+	}
+}
+
+func TestRelocType_IsDWARF(t *testing.T) {
+	if !R_DWARFSECREF.IsDWARF() || !R_DWTXTADDR_U1.IsDWARF() {
+		t.Errorf("should be dwarf")
+	}
+	if R_CALL.IsDWARF() {
+		t.Errorf("should not be dwarf")
+	}
+}
+
+func TestParse_IndexedRelocTypes(t *testing.T) {
+	file := parseFileForTesting(t, helloworldIndexedObjPath)
+	relocs := file.Symbols[0].Relocations
+	if relocs[0].Type != R_USEIFACE || relocs[2].Type != R_PCREL || relocs[6].Type != R_CALL {
+		t.Errorf("invalid relocation types: %v, %v, %v", relocs[0].Type, relocs[2].Type, relocs[6].Type)
+	}
+}
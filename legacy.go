@@ -0,0 +1,179 @@
+package goobj
+
+import "bytes"
+
+// The legacy formats used before go1.9.
+//
+// go17ld (go1.7 and go1.8) is almost same as go19ld, but the symbol kind is the varint
+// of the linker's symbol kinds and the STEXT symbol has neither the pcinline table nor the inlining tree.
+//
+// go13ld (go1.5 and go1.6) has no symbol reference list and no data block. Each symbol has its name,
+// its data and its pcln tables inline, and the relocation has the extra addend and symbol.
+var (
+	magicHeaderGo17 = []byte("\x00\x00go17ld")
+	magicHeaderGo13 = []byte("\x00\x00go13ld")
+)
+
+// footerFor returns the magic footer corresponding to the magic header.
+// The 1st 0xff of the footer is consumed when the symbols are parsed.
+func footerFor(magic []byte) []byte {
+	return append([]byte{0xff}, magic[2:]...)
+}
+
+func isLegacyMagic(b []byte) bool {
+	return bytes.Equal(b, magicHeaderGo17) || bytes.Equal(b, magicHeaderGo13)
+}
+
+// taken from go1.7 and go1.8 cmd/internal/obj (SRODATA and the kinds below it are not written by the compiler).
+var go17SymKinds = map[int64]SymKind{
+	1:  STEXT,
+	8:  SRODATA,
+	28: SNOPTRDATA,
+	30: SDATA,
+	31: SBSS,
+	32: SNOPTRBSS,
+	33: STLSBSS,
+	45: SDWARFINFO,
+}
+
+// taken from go1.5 and go1.6 cmd/internal/obj
+var go13SymKinds = map[int64]SymKind{
+	1:  STEXT,
+	8:  SRODATA,
+	20: SNOPTRDATA,
+	22: SDATA,
+	23: SBSS,
+	24: SNOPTRBSS,
+	25: STLSBSS,
+}
+
+// legacySymKind converts the symbol kind of the legacy format to the SymKind. Sxxx if the kind is unknown.
+func legacySymKind(kinds map[int64]SymKind, kind int64) SymKind {
+	// the upper bits are the attributes like SHIDDEN.
+	const symKindMask = 1<<8 - 1
+	return kinds[kind&symKindMask]
+}
+
+// parseGo13Symbols parses the symbols of the go13ld format. The data and pcln tables of the symbols are
+// concatenated into the data block in the order of appearance, so that the same model as the newer formats can be used.
+func (p *parser) parseGo13Symbols() error {
+	p.SymbolReferences = append(p.SymbolReferences, SymbolReference{})
+	p.referenceIndices = map[SymbolReference]int64{{}: 0}
+	// the data block is not in the file as it is
+	p.DataBlockPosition = -1
+
+	err := p.parseList(p.parseGo13Symbol, nil)
+	p.setLengths()
+	return err
+}
+
+func (p *parser) parseGo13Symbol() error {
+	symbol := Symbol{}
+	symbol.Kind = p.Header.symKindOf(p.Format, p.reader.readVarint())
+	symbol.IDIndex = p.readGo13Reference()
+	p.symbolName = p.referenceName(symbol.IDIndex)
+
+	flags := p.reader.readVarint()
+	symbol.DupOK = flags&0x1 != 0
+
+	symbol.Size = p.reader.readVarint()
+	symbol.GoTypeIndex = p.readGo13Reference()
+	symbol.DataAddr = p.readGo13Data()
+
+	numRelocs := p.reader.readCount("number of relocations", p.reader.limit().MaxRelocations)
+	for i := int64(0); i < numRelocs && p.reader.err == nil; i++ {
+		reloc := Relocation{}
+		reloc.Offset = p.reader.readVarint()
+		reloc.Size = p.reader.readVarint()
+		reloc.Type = p.Header.relocTypeOf(p.Format, p.reader.readVarint())
+		reloc.Add = p.reader.readVarint()
+		// xadd and xsym are used only by the linker
+		_ = p.reader.readVarint()
+		reloc.IDIndex = p.readGo13Reference()
+		_ = p.readGo13Reference()
+
+		symbol.Relocations = append(symbol.Relocations, reloc)
+	}
+
+	if symbol.Kind == STEXT {
+		symbol.Func = p.parseGo13STEXTFields()
+	}
+
+	if p.reader.err != nil {
+		return p.reader.err
+	}
+	p.Symbols = append(p.Symbols, symbol)
+	return nil
+}
+
+func (p *parser) parseGo13STEXTFields() *StextFields {
+	fields := &StextFields{}
+	fields.Args = p.reader.readVarint()
+	fields.Frame = p.reader.readVarint()
+	fields.NoSplit = p.reader.readVarint() != 0
+
+	flags := p.reader.readVarint()
+	fields.Leaf = flags&0x1 != 0
+	fields.CFunc = (flags>>1)&0x1 != 0
+
+	numLocals := p.reader.readVarint()
+	for i := int64(0); i < numLocals && p.reader.err == nil; i++ {
+		local := Local{}
+		local.AsymIndex = p.readGo13Reference()
+		local.Offset = p.reader.readVarint()
+		local.Type = p.reader.readVarint()
+		local.GotypeIndex = p.readGo13Reference()
+
+		fields.Local = append(fields.Local, local)
+	}
+
+	fields.PCSP = p.readGo13Data()
+	fields.PCFile = p.readGo13Data()
+	fields.PCLine = p.readGo13Data()
+
+	numPCData := p.reader.readVarint()
+	for i := int64(0); i < numPCData && p.reader.err == nil; i++ {
+		fields.PCData = append(fields.PCData, p.readGo13Data())
+	}
+
+	numFuncData := p.reader.readVarint()
+	for i := int64(0); i < numFuncData && p.reader.err == nil; i++ {
+		fields.FuncDataIndex = append(fields.FuncDataIndex, p.readGo13Reference())
+	}
+	for i := int64(0); i < numFuncData && p.reader.err == nil; i++ {
+		fields.FuncDataOffset = append(fields.FuncDataOffset, p.reader.readVarint())
+	}
+
+	numFiles := p.reader.readVarint()
+	for i := int64(0); i < numFiles && p.reader.err == nil; i++ {
+		fields.FileIndex = append(fields.FileIndex, p.readGo13Reference())
+	}
+	return fields
+}
+
+// readGo13Reference reads the symbol's name and version, and returns its index of the symbol reference list.
+// The symbol is added to the list when it appears first.
+func (p *parser) readGo13Reference() int64 {
+	reference := SymbolReference{}
+	reference.Name = p.reader.readString()
+	reference.Version = p.reader.readVarint()
+	if p.reader.err != nil {
+		return 0
+	}
+
+	index, ok := p.referenceIndices[reference]
+	if !ok {
+		p.SymbolReferences = append(p.SymbolReferences, reference)
+		index = int64(len(p.SymbolReferences) - 1)
+		p.referenceIndices[reference] = index
+	}
+	return index
+}
+
+// readGo13Data reads the data block and appends it to the File's data block.
+func (p *parser) readGo13Data() DataAddr {
+	data := p.reader.readBytes("data length", p.reader.limit().MaxDataSize-int64(len(p.DataBlock)))
+	addr := DataAddr{Size: int64(len(data)), Offset: int64(len(p.DataBlock))}
+	p.DataBlock = append(p.DataBlock, data...)
+	return addr
+}
@@ -0,0 +1,112 @@
+package goobj
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBytes_Go17(t *testing.T) {
+	obj := "go object linux amd64 go1.8 X:framepointer\n!\n\x00\x00go17ld\x01" +
+		"\x08os.a\x00" + // dependencies
+		"\xfe\x12main.main\x00\xfe\x18gofile..a.go\x00\xff" + // references
+		"\x08\x00\x00\x00\x00\x02" + // lengths
+		"\xc3\x01\x02\x03" + // data
+		"\xfe\x02\x02\x00\x02\x00\x02\x00" + // kind, name, flags, size, gotype, data, relocations
+		"\x00\x00\x00\x00\x00" + // args, frame, nosplit, flags, locals
+		"\x02\x02\x02\x00\x00" + // pcsp, pcfile, pcline, pcdata, funcdata
+		"\x02\x04" + // files
+		"\xff\xffgo17ld"
+	file, err := ParseBytes([]byte(obj))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	if file.Header.GoVersion != "go1.8" {
+		t.Errorf("go version should be go1.8, but %s", file.Header.GoVersion)
+	}
+	if len(file.Symbols) != 1 {
+		t.Fatalf("the number of symbols should be 1, but %d", len(file.Symbols))
+	}
+	symbol := file.Symbols[0]
+	if symbol.Kind != STEXT || symbol.IDIndex != 1 || symbol.DataAddr != (DataAddr{Size: 1, Offset: 0}) {
+		t.Errorf("invalid symbol: %+v", symbol)
+	}
+	if symbol.Func == nil {
+		t.Fatalf("func should not be nil")
+	}
+	expected := &StextFields{
+		PCSP:      DataAddr{Size: 1, Offset: 1},
+		PCFile:    DataAddr{Size: 1, Offset: 2},
+		PCLine:    DataAddr{Size: 1, Offset: 3},
+		FileIndex: []int64{2},
+	}
+	if !reflect.DeepEqual(expected, symbol.Func) {
+		t.Errorf("func should be %+v, but %+v", expected, symbol.Func)
+	}
+}
+
+func TestParseBytes_Go13(t *testing.T) {
+	obj := "go object linux amd64 go1.6 X:none\n!\n\x00\x00go13ld\x01" +
+		"\x00" + // dependencies
+		"\xfe\x02\x12main.main\x00\x00\x02\x00\x00\x02\xc3" + // kind, name, flags, size, gotype, data
+		"\x02\x00\x08\x02\x00\x00\x16fmt.Println\x00\x00\x00" + // relocations
+		"\x00\x00\x00\x00\x00" + // args, frame, nosplit, flags, locals
+		"\x02\x01\x02\x02\x02\x03\x00\x00" + // pcsp, pcfile, pcline, pcdata, funcdata
+		"\x02\x18gofile..a.go\x00" + // files
+		"\xff\xffgo13ld"
+	file, err := ParseBytes([]byte(obj))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	expectedRefs := []SymbolReference{{}, {Name: "main.main"}, {Name: "fmt.Println"}, {Name: "gofile..a.go"}}
+	if !reflect.DeepEqual(expectedRefs, file.SymbolReferences) {
+		t.Errorf("references should be %+v, but %+v", expectedRefs, file.SymbolReferences)
+	}
+	if string(file.DataBlock) != "\xc3\x01\x02\x03" {
+		t.Errorf("invalid data block: %q", file.DataBlock)
+	}
+	if file.DataBlockPosition != -1 {
+		t.Errorf("data block position should be -1, but %d", file.DataBlockPosition)
+	}
+	if file.Header.DataLength != 4 || file.Header.NumRelocations != 1 || file.Header.NumFiles != 1 {
+		t.Errorf("invalid header: %+v", file.Header)
+	}
+
+	if len(file.Symbols) != 1 {
+		t.Fatalf("the number of symbols should be 1, but %d", len(file.Symbols))
+	}
+	symbol := file.Symbols[0]
+	expectedReloc := Relocation{Offset: 0, Size: 4, Type: relocTypeUnconverted | 1, IDIndex: 2}
+	if len(symbol.Relocations) != 1 || symbol.Relocations[0] != expectedReloc {
+		t.Errorf("relocations should be [%+v], but %+v", expectedReloc, symbol.Relocations)
+	}
+	if symbol.Func == nil || symbol.Func.PCLine != (DataAddr{Size: 1, Offset: 3}) || !reflect.DeepEqual([]int64{3}, symbol.Func.FileIndex) {
+		t.Errorf("invalid func: %+v", symbol.Func)
+	}
+}
+
+func TestParseBytes_Go13WrongFooter(t *testing.T) {
+	obj := "\x00\x00go13ld\x01\x00\xff\xffgo17ld"
+	if _, err := ParseBytes([]byte(obj)); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestLegacySymKind(t *testing.T) {
+	for i, testData := range []struct {
+		kinds    map[int64]SymKind
+		in       int64
+		expected SymKind
+	}{
+		{kinds: go17SymKinds, in: 1, expected: STEXT},
+		{kinds: go17SymKinds, in: 28, expected: SNOPTRDATA},
+		{kinds: go17SymKinds, in: 1<<9 | 30, expected: SDATA},
+		{kinds: go17SymKinds, in: 2, expected: Sxxx},
+		{kinds: go13SymKinds, in: 22, expected: SDATA},
+	} {
+		if actual := legacySymKind(testData.kinds, testData.in); actual != testData.expected {
+			t.Errorf("[%d] kind should be %s, but %s", i, testData.expected, actual)
+		}
+	}
+}
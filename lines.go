@@ -0,0 +1,121 @@
+package goobj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineRange represents the source position of the instructions in the [StartPC, EndPC) range of the function.
+type LineRange struct {
+	StartPC, EndPC int64
+	File           string
+	Line           int
+}
+
+// PCRange represents the [StartPC, EndPC) range of the function.
+type PCRange struct {
+	Symbol         *Symbol
+	StartPC, EndPC int64
+}
+
+// LineTable returns the source positions of the function, ordered by pc.
+func (f *File) LineTable(symbol *Symbol) ([]LineRange, error) {
+	fileRanges, err := f.PCFile(symbol)
+	if err != nil {
+		return nil, err
+	}
+	lineRanges, err := f.PCLine(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var table []LineRange
+	i, j := 0, 0
+	for i < len(fileRanges) && j < len(lineRanges) {
+		fileRange, lineRange := fileRanges[i], lineRanges[j]
+		start, end := maxInt64(fileRange.StartPC, lineRange.StartPC), minInt64(fileRange.EndPC, lineRange.EndPC)
+		if start < end {
+			filename, err := f.fileName(symbol, fileRange.Value)
+			if err != nil {
+				return nil, err
+			}
+			table = append(table, LineRange{StartPC: start, EndPC: end, File: filename, Line: int(lineRange.Value)})
+		}
+
+		if fileRange.EndPC <= lineRange.EndPC {
+			i++
+		}
+		if lineRange.EndPC <= fileRange.EndPC {
+			j++
+		}
+	}
+	return table, nil
+}
+
+// LineForPC returns the source position of the instruction at the pc, which is the offset from the beginning of the function.
+func (f *File) LineForPC(symbol *Symbol, pc int64) (file string, line int, err error) {
+	if pc < 0 || pc >= symbol.Size {
+		return "", 0, fmt.Errorf("pc out of the function: %#x", pc)
+	}
+
+	table, err := f.LineTable(symbol)
+	if err != nil {
+		return "", 0, err
+	}
+
+	for _, entry := range table {
+		if entry.StartPC <= pc && pc < entry.EndPC {
+			return entry.File, entry.Line, nil
+		}
+	}
+	return "", 0, fmt.Errorf("no line information for the pc: %#x", pc)
+}
+
+// PCsForLine returns the ranges of the instructions generated from the source line.
+func (f *File) PCsForLine(file string, line int) ([]PCRange, error) {
+	var ranges []PCRange
+	for i := range f.Symbols {
+		symbol := &f.Symbols[i]
+		if symbol.Func == nil {
+			continue
+		}
+
+		table, err := f.LineTable(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range table {
+			if entry.File == file && entry.Line == line {
+				ranges = append(ranges, PCRange{Symbol: symbol, StartPC: entry.StartPC, EndPC: entry.EndPC})
+			}
+		}
+	}
+	return ranges, nil
+}
+
+// fileName returns the name of the file the PCFile table's value specifies.
+func (f *File) fileName(symbol *Symbol, index int64) (string, error) {
+	if index < 0 || index >= int64(len(symbol.Func.FileIndex)) {
+		return "", fmt.Errorf("file index out of range: %d", index)
+	}
+	refIndex := symbol.Func.FileIndex[index]
+	if refIndex < 0 || refIndex >= int64(len(f.SymbolReferences)) {
+		return "", fmt.Errorf("invalid reference index of the file: %d", refIndex)
+	}
+	return strings.TrimPrefix(f.SymbolReferences[refIndex].Name, fileSymbolPrefix), nil
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,78 @@
+package goobj
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFile_LineForPC(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	mainFunc := &file.Symbols[0]
+
+	for i, testData := range []struct {
+		pc           int64
+		expectedLine int
+	}{
+		{pc: 0x0, expectedLine: 7},
+		{pc: 0x1d, expectedLine: 8},
+		{pc: 0x5c, expectedLine: 8},
+		{pc: 0x5d, expectedLine: 9},
+		{pc: 0x6d, expectedLine: 7},
+	} {
+		filename, line, err := file.LineForPC(mainFunc, testData.pc)
+		if err != nil {
+			t.Fatalf("[%d] error should be nil, but %v", i, err)
+		}
+		if !strings.HasSuffix(filename, "/helloworld.go") {
+			t.Errorf("[%d] invalid file name: %s", i, filename)
+		}
+		if line != testData.expectedLine {
+			t.Errorf("[%d] line should be %d, but %d", i, testData.expectedLine, line)
+		}
+	}
+}
+
+func TestFile_LineForPC_OutOfFunction(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	if _, _, err := file.LineForPC(&file.Symbols[0], file.Symbols[0].Size); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_PCsForLine(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	filename, _, _ := file.LineForPC(&file.Symbols[0], 0)
+
+	actual, err := file.PCsForLine(filename, 7)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := []PCRange{{Symbol: &file.Symbols[0], StartPC: 0x0, EndPC: 0x1d}, {Symbol: &file.Symbols[0], StartPC: 0x67, EndPC: 0x6e}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("ranges should be %+v, but %+v", expected, actual)
+	}
+}
+
+func TestFile_LineTable_DifferentBoundaries(t *testing.T) {
+	file := &File{
+		SymbolReferences: []SymbolReference{{}, {Name: "gofile..a.go"}, {Name: "gofile..b.go"}},
+		// PCFile: [0, 4) => 0, [4, 8) => 1
+		// PCLine: [0, 2) => 10, [2, 8) => 11
+		DataBlock: []byte("\x02\x04\x02\x04\x00" + "\x16\x02\x02\x06\x00"),
+	}
+	symbol := &Symbol{Size: 8, Func: &StextFields{
+		PCFile:    DataAddr{Size: 5, Offset: 0},
+		PCLine:    DataAddr{Size: 5, Offset: 5},
+		FileIndex: []int64{1, 2},
+	}}
+
+	actual, err := file.LineTable(symbol)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := []LineRange{{0, 2, "a.go", 10}, {2, 4, "a.go", 11}, {4, 8, "b.go", 11}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("table should be %+v, but %+v", expected, actual)
+	}
+}
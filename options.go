@@ -0,0 +1,78 @@
+package goobj
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Limits restricts the resources the parser uses, so that the crafted file does not exhaust the memory.
+// The zero value of each field means the value of DefaultLimits.
+type Limits struct {
+	// MaxDataSize is the maximum size of the data block.
+	MaxDataSize int64
+	// MaxObjectSize is the maximum size of the object file of the indexed format, which is read into the memory at once.
+	// Scanner reads only the blocks before the data block, and their size is limited instead.
+	MaxObjectSize int64
+	// MaxHeaderSize is the maximum size of the bytes before the magic header, such as the header line and the export data.
+	MaxHeaderSize int64
+	// MaxStringLength is the maximum length of the strings like the symbol names and the imported packages.
+	MaxStringLength int64
+	// MaxRelocations is the maximum number of the relocations of each symbol.
+	MaxRelocations int64
+	// MaxVarintLength is the maximum number of the bytes of the varint.
+	MaxVarintLength int
+}
+
+// DefaultLimits is the limits used when no limit is specified.
+var DefaultLimits = Limits{
+	MaxDataSize:     1 << 30,
+	MaxObjectSize:   1 << 30,
+	MaxHeaderSize:   1 << 26,
+	MaxStringLength: 1 << 24,
+	MaxRelocations:  1 << 24,
+	MaxVarintLength: binary.MaxVarintLen64,
+}
+
+// withDefaults returns the limits whose zero fields are replaced with DefaultLimits.
+func (l Limits) withDefaults() Limits {
+	if l.MaxDataSize == 0 {
+		l.MaxDataSize = DefaultLimits.MaxDataSize
+	}
+	if l.MaxObjectSize == 0 {
+		l.MaxObjectSize = DefaultLimits.MaxObjectSize
+	}
+	if l.MaxHeaderSize == 0 {
+		l.MaxHeaderSize = DefaultLimits.MaxHeaderSize
+	}
+	if l.MaxStringLength == 0 {
+		l.MaxStringLength = DefaultLimits.MaxStringLength
+	}
+	if l.MaxRelocations == 0 {
+		l.MaxRelocations = DefaultLimits.MaxRelocations
+	}
+	if l.MaxVarintLength == 0 {
+		l.MaxVarintLength = DefaultLimits.MaxVarintLength
+	}
+	return l
+}
+
+// ParseOptions configures the parser.
+type ParseOptions struct {
+	Limits Limits
+	// Lenient makes the parser record the errors into File.Diagnostics and return the symbols recovered so far.
+	// The broken symbol or reference is skipped until the next marker of the symbol or reference.
+	// The skipped reference is replaced with the reference named "<broken>", so that the indices of the other references are kept.
+	// Note that the data addresses of the symbols after the broken symbol may be wrong.
+	Lenient bool
+}
+
+// checkLength returns the error if the length read from the file is negative or exceeds the limit.
+func checkLength(name string, length, limit int64) error {
+	if length < 0 {
+		return fmt.Errorf("negative %s: %d", name, length)
+	}
+	if length > limit {
+		return fmt.Errorf("too large %s: %d (limit %d)", name, length, limit)
+	}
+	return nil
+}
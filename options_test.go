@@ -0,0 +1,176 @@
+package goobj
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseReaderWithOptions_Limits(t *testing.T) {
+	const header = "\x00\x00go19ld\x01\x00\xff"
+	for i, testData := range []struct {
+		in       string
+		limits   Limits
+		expected string
+	}{
+		// the huge data length in the small file
+		{in: header + "\xfe\xff\xff\xff\xff\x0f\x00\x00\x00\x00\x00", expected: "too large data length"},
+		{in: header + "\x20\x00\x00\x00\x00\x00", limits: Limits{MaxDataSize: 15}, expected: "too large data length"},
+		{in: header + "\x01\x00\x00\x00\x00\x00", expected: "negative data length"},
+		{in: header + "\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff", expected: "too long varint"},
+		{in: header + "\xff\xff\x01", limits: Limits{MaxVarintLength: 2}, expected: "too long varint"},
+		{in: "\x00\x00go19ld\x01\x00\xfe\x01", expected: "negative string length"},
+		{in: "\x00\x00go19ld\x01\x00\xfe\x22", limits: Limits{MaxStringLength: 16}, expected: "too large string length"},
+		{in: header + "\x00\x00\x00\x00\x00\x00\xfe\x02\x00\x00\x00\x00\x00\x22", limits: Limits{MaxRelocations: 16}, expected: "too large number of relocations"},
+	} {
+		_, err := ParseReaderWithOptions(strings.NewReader(testData.in), ParseOptions{Limits: testData.limits})
+		if err == nil || !strings.Contains(err.Error(), testData.expected) {
+			t.Errorf("[%d] error should contain %q, but %v", i, testData.expected, err)
+		}
+	}
+}
+
+func TestParseReaderWithOptions_IndexedLimit(t *testing.T) {
+	in := "\x00go120ld" + strings.Repeat("\x00", 100)
+	_, err := ParseReaderWithOptions(strings.NewReader(in), ParseOptions{Limits: Limits{MaxObjectSize: 50}})
+	if err == nil || !strings.Contains(err.Error(), "too large object file") {
+		t.Errorf("error should be the too large object file, but %v", err)
+	}
+
+	// the data block size is not the limit of the object file
+	_, err = ParseReaderWithOptions(strings.NewReader(in), ParseOptions{Limits: Limits{MaxDataSize: 50}})
+	if err != nil && strings.Contains(err.Error(), "too large object file") {
+		t.Errorf("error should not be the too large object file, but %v", err)
+	}
+}
+
+func TestParseReaderWithOptions_HeaderLimit(t *testing.T) {
+	in := "go object linux amd64 go1.10 X:none\n" + strings.Repeat("export data\n", 10) + "!\n\x00\x00go19ld\x01"
+	_, err := ParseReaderWithOptions(strings.NewReader(in), ParseOptions{Limits: Limits{MaxHeaderSize: 64}})
+	if err == nil || !strings.Contains(err.Error(), "too large header") {
+		t.Errorf("error should be the too large header, but %v", err)
+	}
+
+	file := parseFileForTesting(t, helloworldObjPath)
+	obj, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if _, err := ParseReaderWithOptions(bytes.NewReader(obj), ParseOptions{Limits: Limits{MaxHeaderSize: int64(len(file.RawHeader))}}); err != nil {
+		t.Errorf("error should be nil, but %v", err)
+	}
+}
+
+func TestReaderWithCounter_readN_Truncated(t *testing.T) {
+	r := readerWithCounter{raw: bufio.NewReader(strings.NewReader("ab"))}
+	if data := r.readN(1 << 40); data != nil || r.err == nil {
+		t.Errorf("error should not be nil")
+	}
+	if r.numReadBytes != 2 {
+		t.Errorf("the number of read bytes should be 2, but %d", r.numReadBytes)
+	}
+}
+
+func TestLimits_withDefaults(t *testing.T) {
+	limits := Limits{MaxStringLength: 1}.withDefaults()
+	expected := DefaultLimits
+	expected.MaxStringLength = 1
+	if limits != expected {
+		t.Errorf("limits should be %+v, but %+v", expected, limits)
+	}
+}
+
+func TestParseReaderWithOptions_Lenient(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".a`, SRODATA, []byte{1})
+	b.AddSymbol(`"".b`, SRODATA, []byte{2})
+	b.AddSymbol(`"".c`, SRODATA, []byte{3})
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	// break the marker of the 2nd symbol. Each symbol is 8 bytes.
+	file, _ := ParseBytes(obj)
+	obj[file.DataBlockPosition+file.Header.DataLength+8] = 0x3
+	if _, err := ParseBytes(obj); err == nil {
+		t.Fatalf("error should not be nil")
+	}
+
+	file, err = ParseReaderWithOptions(bytes.NewReader(obj), ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if len(file.Symbols) != 2 {
+		t.Fatalf("the number of symbols should be 2, but %d", len(file.Symbols))
+	}
+	for i, expected := range []string{`"".a`, `"".c`} {
+		if name := file.SymbolReferences[file.Symbols[i].IDIndex].Name; name != expected {
+			t.Errorf("[%d] name should be %s, but %s", i, expected, name)
+		}
+	}
+	if len(file.Diagnostics) == 0 || file.Diagnostics[0].SymbolIndex != 1 {
+		t.Errorf("the diagnostic of the 2nd symbol should be recorded: %v", file.Diagnostics)
+	}
+}
+
+func TestParseReaderWithOptions_Lenient_BrokenReference(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".a`, SRODATA, []byte{1})
+	b.AddSymbol(`"".b`, SRODATA, []byte{2})
+	b.AddSymbol(`"".c`, SRODATA, []byte{3})
+	obj, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	// break the marker of the reference to "".b
+	i := bytes.Index(obj, []byte("\xfe\x08\"\".b"))
+	if i < 0 {
+		t.Fatalf("the reference not found")
+	}
+	obj[i] = 0x3
+
+	file, err := ParseReaderWithOptions(bytes.NewReader(obj), ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if len(file.Symbols) != 3 {
+		t.Fatalf("the number of symbols should be 3, but %d", len(file.Symbols))
+	}
+	for i, expected := range []string{`"".a`, brokenReferenceName, `"".c`} {
+		if name := file.SymbolReferences[file.Symbols[i].IDIndex].Name; name != expected {
+			t.Errorf("[%d] name should be %s, but %s", i, expected, name)
+		}
+	}
+	if len(file.Diagnostics) != 1 || file.Diagnostics[0].Section != SectionReferences {
+		t.Errorf("the diagnostic of the reference should be recorded: %v", file.Diagnostics)
+	}
+}
+
+func TestParseReaderWithOptions_Lenient_Truncated(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	file, err := ParseReaderWithOptions(bytes.NewReader(data[:len(data)-100]), ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if len(file.Symbols) == 0 || len(file.SymbolReferences) == 0 {
+		t.Errorf("the symbols should be recovered")
+	}
+	if len(file.Diagnostics) != 1 || file.Diagnostics[0].Err != io.ErrUnexpectedEOF {
+		t.Errorf("the diagnostic should be the unexpected EOF: %v", file.Diagnostics)
+	}
+}
+
+func TestParseReaderWithOptions_Lenient_NoMagic(t *testing.T) {
+	if _, err := ParseReaderWithOptions(strings.NewReader("not a go object file"), ParseOptions{Lenient: true}); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
@@ -2,10 +2,14 @@ package goobj
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 )
 
 const supportedGoObjVersion = 1
@@ -13,19 +17,51 @@ const supportedGoObjVersion = 1
 var magicHeader = []byte("\x00\x00go19ld")
 var magicFooter = []byte("\xffgo19ld")
 
+// the name of the symbol which represents a source file has this prefix.
+const fileSymbolPrefix = "gofile.."
+
 // File represents a go object file.
 type File struct {
+	// Imports is the list of the packages the object file depends on.
+	Imports          []string
 	Symbols          []Symbol
 	SymbolReferences []SymbolReference
 	DataBlock        []byte
 	// the data block starts at this position of the object file
 	DataBlockPosition int64
+	Header            Header
+	// RawHeader is the bytes before the magic header, such as the header line and the export data.
+	RawHeader []byte
+	// Format is the format of the object file. The file built by ObjectBuilder is FormatGo19.
+	Format Format
+	// Diagnostics is the list of the errors found in the lenient mode. See ParseOptions.
+	Diagnostics []*ParseError
+	// the cache of SymbolsByName.
+	symbolsByName map[string]int
+}
+
+// Header represents the header line of the object file and the total lengths written before the data block.
+type Header struct {
+	// The header line is like `go object linux amd64 go1.10 X:framepointer`.
+	GOOS        string
+	GOARCH      string
+	GoVersion   string
+	Experiments []string
+
+	DataLength     int64
+	NumRelocations int64
+	NumPCData      int64
+	NumLocals      int64
+	NumFuncData    int64
+	NumFiles       int64
 }
 
 // SymbolReference represents a symbol's name and its version.
 type SymbolReference struct {
 	Name    string
 	Version int64
+	// ABI is the symbol's ABI in the indexed format (go1.16 and later). Always 0 in the go19ld format.
+	ABI int64
 }
 
 // Symbol describes metadata associated with data block.
@@ -39,8 +75,8 @@ type Symbol struct {
 	GoTypeIndex int64
 	DataAddr    DataAddr
 	Relocations []Relocation
-	// STEXT type has additional fields
-	stextFields *StextFields
+	// Func holds the additional metadata of the STEXT-type symbol. Nil for other types.
+	Func *StextFields
 }
 
 // SymKind represents a type of symbol
@@ -68,6 +104,26 @@ const (
 	SDWARFINFO
 	SDWARFRANGE
 	SDWARFLOC
+
+	// The following kinds are added after go1.10. See cmd/internal/objabi of the newer releases for the details.
+	SDWARFMISC
+	SABIALIAS
+	SLIBFUZZER_EXTRA_COUNTER
+	SDWARFCUINFO
+	SDWARFCONST
+	SDWARFFCN
+	SDWARFABSFCN
+	SDWARFTYPE
+	SDWARFVAR
+	SDWARFLINES
+	SDWARFADDR
+	SCOVERAGE_COUNTER
+	SCOVERAGE_AUXVAR
+	SSEHUNWINDINFO
+	STEXTFIPS
+	SRODATAFIPS
+	SNOPTRDATAFIPS
+	SDATAFIPS
 )
 
 func (kind SymKind) String() string {
@@ -94,6 +150,42 @@ func (kind SymKind) String() string {
 		return "SDWARFRANGE"
 	case SDWARFLOC:
 		return "SDWARFLOC"
+	case SDWARFMISC:
+		return "SDWARFMISC"
+	case SABIALIAS:
+		return "SABIALIAS"
+	case SLIBFUZZER_EXTRA_COUNTER:
+		return "SLIBFUZZER_EXTRA_COUNTER"
+	case SDWARFCUINFO:
+		return "SDWARFCUINFO"
+	case SDWARFCONST:
+		return "SDWARFCONST"
+	case SDWARFFCN:
+		return "SDWARFFCN"
+	case SDWARFABSFCN:
+		return "SDWARFABSFCN"
+	case SDWARFTYPE:
+		return "SDWARFTYPE"
+	case SDWARFVAR:
+		return "SDWARFVAR"
+	case SDWARFLINES:
+		return "SDWARFLINES"
+	case SDWARFADDR:
+		return "SDWARFADDR"
+	case SCOVERAGE_COUNTER:
+		return "SCOVERAGE_COUNTER"
+	case SCOVERAGE_AUXVAR:
+		return "SCOVERAGE_AUXVAR"
+	case SSEHUNWINDINFO:
+		return "SSEHUNWINDINFO"
+	case STEXTFIPS:
+		return "STEXTFIPS"
+	case SRODATAFIPS:
+		return "SRODATAFIPS"
+	case SNOPTRDATAFIPS:
+		return "SNOPTRDATAFIPS"
+	case SDATAFIPS:
+		return "SDATAFIPS"
 	default:
 		return "UNKNOWN"
 	}
@@ -272,6 +364,78 @@ const (
 	// R_ADDRCUOFF resolves to a pointer-sized offset from the start of the
 	// symbol's DWARF compile unit.
 	R_ADDRCUOFF // 44
+
+	// The following types are added after go1.10. See cmd/internal/objabi of the newer releases for the details.
+	R_USEIFACE
+	R_USEIFACEMETHOD
+	R_USENAMEDMETHOD
+	R_KEEP
+	R_ARM64_GOT
+	R_ARM64_PCREL
+	R_ARM64_PCREL_LDST8
+	R_ARM64_PCREL_LDST16
+	R_ARM64_PCREL_LDST32
+	R_ARM64_PCREL_LDST64
+	R_ARM64_LDST8
+	R_ARM64_LDST16
+	R_ARM64_LDST32
+	R_ARM64_LDST64
+	R_ARM64_LDST128
+	R_POWER_TLS_IE_PCREL34
+	R_POWER_TLS_LE_TPREL34
+	R_ADDRPOWER_GOT_PCREL34
+	R_ADDRPOWER_D34
+	R_ADDRPOWER_PCREL34
+	R_RISCV_JAL
+	R_RISCV_JAL_TRAMP
+	R_RISCV_CALL
+	R_RISCV_PCREL_ITYPE
+	R_RISCV_PCREL_STYPE
+	R_RISCV_TLS_IE
+	R_RISCV_TLS_LE
+	R_RISCV_GOT_HI20
+	R_RISCV_GOT_PCREL_ITYPE
+	R_RISCV_PCREL_HI20
+	R_RISCV_PCREL_LO12_I
+	R_RISCV_PCREL_LO12_S
+	R_RISCV_BRANCH
+	R_RISCV_ADD32
+	R_RISCV_SUB32
+	R_RISCV_RVC_BRANCH
+	R_RISCV_RVC_JUMP
+	R_LOONG64_ADDR_HI
+	R_LOONG64_ADDR_LO
+	R_LOONG64_ADDR64_HI
+	R_LOONG64_ADDR64_LO
+	R_LOONG64_ADDR_PCREL20_S2
+	R_LOONG64_TLS_LE_HI
+	R_LOONG64_TLS_LE_LO
+	R_CALLLOONG64
+	R_LOONG64_CALL36
+	R_LOONG64_TLS_IE_HI
+	R_LOONG64_TLS_IE_LO
+	R_LOONG64_GOT_HI
+	R_LOONG64_GOT_LO
+	R_LOONG64_GOT64_HI
+	R_LOONG64_GOT64_LO
+	R_LOONG64_ADD64
+	R_LOONG64_SUB64
+	R_JMP16LOONG64
+	R_JMP21LOONG64
+	R_WASMIMPORT
+	R_XCOFFREF
+	R_PEIMAGEOFF
+	R_INITORDER
+	R_DWTXTADDR_U1
+	R_DWTXTADDR_U2
+	R_DWTXTADDR_U3
+	R_DWTXTADDR_U4
+
+	// The following types are removed before go1.27.
+	R_RISCV_TLS_IE_ITYPE
+	R_RISCV_TLS_IE_STYPE
+	R_JMPLOONG64
+	R_WEAKADDR
 )
 
 func (relocType RelocType) String() string {
@@ -364,7 +528,146 @@ func (relocType RelocType) String() string {
 		return "R_ADDRMIPSTLS"
 	case R_ADDRCUOFF:
 		return "R_ADDRCUOFF"
+	case R_USEIFACE:
+		return "R_USEIFACE"
+	case R_USEIFACEMETHOD:
+		return "R_USEIFACEMETHOD"
+	case R_USENAMEDMETHOD:
+		return "R_USENAMEDMETHOD"
+	case R_KEEP:
+		return "R_KEEP"
+	case R_ARM64_GOT:
+		return "R_ARM64_GOT"
+	case R_ARM64_PCREL:
+		return "R_ARM64_PCREL"
+	case R_ARM64_PCREL_LDST8:
+		return "R_ARM64_PCREL_LDST8"
+	case R_ARM64_PCREL_LDST16:
+		return "R_ARM64_PCREL_LDST16"
+	case R_ARM64_PCREL_LDST32:
+		return "R_ARM64_PCREL_LDST32"
+	case R_ARM64_PCREL_LDST64:
+		return "R_ARM64_PCREL_LDST64"
+	case R_ARM64_LDST8:
+		return "R_ARM64_LDST8"
+	case R_ARM64_LDST16:
+		return "R_ARM64_LDST16"
+	case R_ARM64_LDST32:
+		return "R_ARM64_LDST32"
+	case R_ARM64_LDST64:
+		return "R_ARM64_LDST64"
+	case R_ARM64_LDST128:
+		return "R_ARM64_LDST128"
+	case R_POWER_TLS_IE_PCREL34:
+		return "R_POWER_TLS_IE_PCREL34"
+	case R_POWER_TLS_LE_TPREL34:
+		return "R_POWER_TLS_LE_TPREL34"
+	case R_ADDRPOWER_GOT_PCREL34:
+		return "R_ADDRPOWER_GOT_PCREL34"
+	case R_ADDRPOWER_D34:
+		return "R_ADDRPOWER_D34"
+	case R_ADDRPOWER_PCREL34:
+		return "R_ADDRPOWER_PCREL34"
+	case R_RISCV_JAL:
+		return "R_RISCV_JAL"
+	case R_RISCV_JAL_TRAMP:
+		return "R_RISCV_JAL_TRAMP"
+	case R_RISCV_CALL:
+		return "R_RISCV_CALL"
+	case R_RISCV_PCREL_ITYPE:
+		return "R_RISCV_PCREL_ITYPE"
+	case R_RISCV_PCREL_STYPE:
+		return "R_RISCV_PCREL_STYPE"
+	case R_RISCV_TLS_IE:
+		return "R_RISCV_TLS_IE"
+	case R_RISCV_TLS_LE:
+		return "R_RISCV_TLS_LE"
+	case R_RISCV_GOT_HI20:
+		return "R_RISCV_GOT_HI20"
+	case R_RISCV_GOT_PCREL_ITYPE:
+		return "R_RISCV_GOT_PCREL_ITYPE"
+	case R_RISCV_PCREL_HI20:
+		return "R_RISCV_PCREL_HI20"
+	case R_RISCV_PCREL_LO12_I:
+		return "R_RISCV_PCREL_LO12_I"
+	case R_RISCV_PCREL_LO12_S:
+		return "R_RISCV_PCREL_LO12_S"
+	case R_RISCV_BRANCH:
+		return "R_RISCV_BRANCH"
+	case R_RISCV_ADD32:
+		return "R_RISCV_ADD32"
+	case R_RISCV_SUB32:
+		return "R_RISCV_SUB32"
+	case R_RISCV_RVC_BRANCH:
+		return "R_RISCV_RVC_BRANCH"
+	case R_RISCV_RVC_JUMP:
+		return "R_RISCV_RVC_JUMP"
+	case R_LOONG64_ADDR_HI:
+		return "R_LOONG64_ADDR_HI"
+	case R_LOONG64_ADDR_LO:
+		return "R_LOONG64_ADDR_LO"
+	case R_LOONG64_ADDR64_HI:
+		return "R_LOONG64_ADDR64_HI"
+	case R_LOONG64_ADDR64_LO:
+		return "R_LOONG64_ADDR64_LO"
+	case R_LOONG64_ADDR_PCREL20_S2:
+		return "R_LOONG64_ADDR_PCREL20_S2"
+	case R_LOONG64_TLS_LE_HI:
+		return "R_LOONG64_TLS_LE_HI"
+	case R_LOONG64_TLS_LE_LO:
+		return "R_LOONG64_TLS_LE_LO"
+	case R_CALLLOONG64:
+		return "R_CALLLOONG64"
+	case R_LOONG64_CALL36:
+		return "R_LOONG64_CALL36"
+	case R_LOONG64_TLS_IE_HI:
+		return "R_LOONG64_TLS_IE_HI"
+	case R_LOONG64_TLS_IE_LO:
+		return "R_LOONG64_TLS_IE_LO"
+	case R_LOONG64_GOT_HI:
+		return "R_LOONG64_GOT_HI"
+	case R_LOONG64_GOT_LO:
+		return "R_LOONG64_GOT_LO"
+	case R_LOONG64_GOT64_HI:
+		return "R_LOONG64_GOT64_HI"
+	case R_LOONG64_GOT64_LO:
+		return "R_LOONG64_GOT64_LO"
+	case R_LOONG64_ADD64:
+		return "R_LOONG64_ADD64"
+	case R_LOONG64_SUB64:
+		return "R_LOONG64_SUB64"
+	case R_JMP16LOONG64:
+		return "R_JMP16LOONG64"
+	case R_JMP21LOONG64:
+		return "R_JMP21LOONG64"
+	case R_WASMIMPORT:
+		return "R_WASMIMPORT"
+	case R_XCOFFREF:
+		return "R_XCOFFREF"
+	case R_PEIMAGEOFF:
+		return "R_PEIMAGEOFF"
+	case R_INITORDER:
+		return "R_INITORDER"
+	case R_DWTXTADDR_U1:
+		return "R_DWTXTADDR_U1"
+	case R_DWTXTADDR_U2:
+		return "R_DWTXTADDR_U2"
+	case R_DWTXTADDR_U3:
+		return "R_DWTXTADDR_U3"
+	case R_DWTXTADDR_U4:
+		return "R_DWTXTADDR_U4"
+	case R_RISCV_TLS_IE_ITYPE:
+		return "R_RISCV_TLS_IE_ITYPE"
+	case R_RISCV_TLS_IE_STYPE:
+		return "R_RISCV_TLS_IE_STYPE"
+	case R_JMPLOONG64:
+		return "R_JMPLOONG64"
+	case R_WEAKADDR:
+		return "R_WEAKADDR"
 	default:
+		if raw, ok := relocType.Unconverted(); ok {
+			return fmt.Sprintf("RelocType(%d)", raw)
+		}
 		return "Unknown"
 	}
 }
@@ -388,6 +691,20 @@ type StextFields struct {
 	FuncDataIndex  []int64
 	FuncDataOffset []int64
 	FileIndex      []int64
+	InlineTree     []InlinedCall
+}
+
+// InlinedCall represents a node of the inlining tree, that is, a call inlined into the function.
+type InlinedCall struct {
+	// Parent is the index of the node this call is inlined into. -1 if the call is inlined into the function itself.
+	Parent int64
+	// File and Line is the position of the call.
+	File      string
+	FileIndex int64
+	Line      int64
+	// Func is the name of the inlined function.
+	Func      string
+	FuncIndex int64
 }
 
 // Local represents a local variable including input args and output.
@@ -405,39 +722,102 @@ type DataAddr struct {
 
 // Parse parses a given go object file
 func Parse(f *os.File) (*File, error) {
-	parser := newParser(bufio.NewReader(f))
+	return ParseReader(f)
+}
+
+// ParseBytes parses a go object file in memory.
+func ParseBytes(b []byte) (*File, error) {
+	return ParseReader(bytes.NewReader(b))
+}
+
+// ParseReaderAt parses a go object file which is the first size bytes of the given reader.
+func ParseReaderAt(r io.ReaderAt, size int64) (*File, error) {
+	return ParseReader(io.NewSectionReader(r, 0, size))
+}
+
+// ParseReader parses a go object file read from the given reader.
+func ParseReader(r io.Reader) (*File, error) {
+	return ParseReaderWithOptions(r, ParseOptions{})
+}
+
+// ParseReaderWithOptions parses a go object file read from the given reader with the options.
+func ParseReaderWithOptions(r io.Reader, options ParseOptions) (*File, error) {
+	parser := newParser(bufio.NewReader(r))
+	parser.reader.limits = options.Limits
+	parser.lenient = options.Lenient
 	if err := parser.skipHeader(); err != nil {
-		return nil, err
+		return nil, parser.parseError(err)
 	}
 
-	if err := parser.checkVersion(); err != nil {
-		return nil, err
+	if err := parser.read(parser); err != nil {
+		return nil, parser.parseError(err)
 	}
+	return &parser.File, nil
+}
 
-	if err := parser.skipDependencies(); err != nil {
-		return nil, err
-	}
+// readGo19 reads the go19ld and go17ld formats.
+func (p *parser) readGo19() error {
+	return p.parseSections([]section{
+		{SectionHeader, p.checkVersion},
+		{SectionDependencies, p.parseDependencies},
+		{SectionReferences, p.parseReferences},
+		{SectionData, p.parseData},
+		{SectionSymbols, p.parseSymbols},
+		{SectionFooter, p.checkHeader},
+		{SectionFooter, p.skipFooter},
+	})
+}
 
-	if err := parser.parseReferences(); err != nil {
-		return nil, err
-	}
+// readGo13 reads the go13ld format.
+func (p *parser) readGo13() error {
+	return p.parseSections([]section{
+		{SectionHeader, p.checkVersion},
+		{SectionDependencies, p.parseDependencies},
+		{SectionSymbols, p.parseGo13Symbols},
+		{SectionFooter, p.skipFooter},
+	})
+}
 
-	if err := parser.parseData(); err != nil {
-		return nil, err
-	}
+type section struct {
+	section Section
+	parse   func() error
+}
 
-	if err := parser.parseSymbols(); err != nil {
-		return nil, err
+// parseSections parses the sections in order. In the lenient mode, the error is recorded as the diagnostic and
+// the next section is parsed, unless the file is truncated.
+func (p *parser) parseSections(sections []section) error {
+	for _, section := range sections {
+		p.section = section.section
+		if err := section.parse(); err != nil {
+			if !p.lenient {
+				return err
+			}
+
+			p.addDiagnostic(err)
+			if p.reader.atEOF() {
+				return nil
+			}
+			p.reader.err = nil
+		}
 	}
-
-	return &parser.File, parser.skipFooter()
+	return nil
 }
 
 type parser struct {
 	reader readerWithCounter
+	// the reader of the format and the magic header found by skipHeader.
+	read  func(p *parser) error
+	magic []byte
+	// the index of each symbol reference. Used only by the go13ld format, which has no symbol reference list.
+	referenceIndices map[SymbolReference]int64
 	// As a list of symbols are parsed, a symbol is associated with some region of the data block.
 	// associatedDataSize is the total size of those regions.
 	associatedDataSize int64
+	// the section and the name of the symbol being parsed, which are reported by ParseError.
+	section    Section
+	symbolName string
+	// lenient is true if the errors are recorded as the diagnostics rather than returned.
+	lenient bool
 	File
 }
 
@@ -446,19 +826,15 @@ func newParser(raw *bufio.Reader) *parser {
 }
 
 func (p *parser) skipHeader() error {
-	buff := make([]byte, len(magicHeader))
-	_ = p.reader.read(buff)
-	if p.reader.err != nil {
-		return p.reader.err
+	if err := p.detect(); err != nil {
+		return err
 	}
 
-	for !reflect.DeepEqual(buff, magicHeader) {
-		b := p.reader.readByte()
-		if p.reader.err != nil {
-			return errors.New("magic header not found")
-		}
-
-		buff = append(buff[1:], b)
+	switch p.Format {
+	case FormatUnknown:
+		return errors.New("magic header not found")
+	case FormatArchive:
+		return errors.New("the file is the package archive, not the object file")
 	}
 	return nil
 }
@@ -469,22 +845,23 @@ func (p *parser) checkVersion() error {
 		return p.reader.err
 	}
 
-	if version != 1 {
-		return fmt.Errorf("unexpected version: %d", version)
+	if version != supportedGoObjVersion {
+		return fmt.Errorf("unexpected version of the %s format (%s): %d", p.Format, p.Format.Toolchain(), version)
 	}
 	return nil
 }
 
-func (p *parser) skipDependencies() error {
+func (p *parser) parseDependencies() error {
 	for {
-		b := p.reader.readByte()
+		dependency := p.reader.readString()
 		if p.reader.err != nil {
 			return p.reader.err
 		}
 
-		if b == 0 {
+		if dependency == "" {
 			return nil
 		}
+		p.Imports = append(p.Imports, dependency)
 	}
 }
 
@@ -492,22 +869,7 @@ func (p *parser) parseReferences() error {
 	// the 1st reference is always empty.
 	p.SymbolReferences = append(p.SymbolReferences, SymbolReference{})
 
-	for {
-		b := p.reader.readByte()
-		if p.reader.err != nil {
-			return p.reader.err
-		}
-
-		if b == 0xff {
-			return nil
-		} else if b != 0xfe {
-			return fmt.Errorf("sanity check failed: %#x ", b)
-		}
-
-		if err := p.parseReference(); err != nil {
-			return err
-		}
-	}
+	return p.parseList(p.parseReference, p.addBrokenReference)
 }
 
 func (p *parser) parseReference() error {
@@ -521,58 +883,120 @@ func (p *parser) parseReference() error {
 		return p.reader.err
 	}
 
-	p.SymbolReferences = append(p.SymbolReferences, SymbolReference{symbolName, symbolVersion})
+	p.SymbolReferences = append(p.SymbolReferences, SymbolReference{Name: symbolName, Version: symbolVersion})
 	return nil
 }
 
+// brokenReferenceName is the name of the reference skipped in the lenient mode.
+const brokenReferenceName = "<broken>"
+
+// addBrokenReference adds the placeholder of the skipped reference, so that the indices of the later references are kept.
+func (p *parser) addBrokenReference() {
+	p.SymbolReferences = append(p.SymbolReferences, SymbolReference{Name: brokenReferenceName})
+}
+
 func (p *parser) parseData() error {
+	if err := p.parseLengths(); err != nil {
+		return err
+	}
+
+	p.DataBlockPosition = p.reader.numReadBytes
+	p.DataBlock = p.reader.readN(p.Header.DataLength)
+	return p.reader.err
+}
+
+// parseLengths parses the lengths written before the data block.
+func (p *parser) parseLengths() error {
 	dataLength := p.reader.readVarint()
 	if p.reader.err != nil {
 		return p.reader.err
 	}
-	_ = p.reader.readVarint() // reloc
-	_ = p.reader.readVarint() // pcdata
-	_ = p.reader.readVarint() // automatics
-	_ = p.reader.readVarint() // funcdata
-	_ = p.reader.readVarint() // files
-
-	p.DataBlockPosition = p.reader.numReadBytes
-	p.DataBlock = make([]byte, dataLength)
-	numRead := 0
-	for numRead != int(dataLength) {
-		n := p.reader.read(p.DataBlock[numRead:])
-		if p.reader.err != nil {
-			return p.reader.err
-		}
-		numRead += n
+	p.Header.DataLength = dataLength
+	p.Header.NumRelocations = p.reader.readVarint()
+	p.Header.NumPCData = p.reader.readVarint()
+	p.Header.NumLocals = p.reader.readVarint()
+	p.Header.NumFuncData = p.reader.readVarint()
+	p.Header.NumFiles = p.reader.readVarint()
+	if p.reader.err != nil {
+		return p.reader.err
 	}
 
-	return nil
+	return checkLength("data length", dataLength, p.reader.limit().MaxDataSize)
 }
 
 func (p *parser) parseSymbols() error {
+	return p.parseList(p.parseSymbol, nil)
+}
+
+// parseList parses the list whose each item starts with 0xfe. The list ends with 0xff.
+// In the lenient mode, the broken item is recorded as the diagnostic and skipped until the next 0xfe.
+// skipItem, if not nil, is called when the item is skipped.
+func (p *parser) parseList(parseItem func() error, skipItem func()) error {
 	for {
+		p.symbolName = ""
 		b := p.reader.readByte()
 		if p.reader.err != nil {
 			return p.reader.err
 		}
 
+		var err error
 		if b == 0xff {
 			return nil
 		} else if b != 0xfe {
-			return fmt.Errorf("sanity check failed: %#x ", b)
+			err = fmt.Errorf("sanity check failed: %#x", b)
+		} else {
+			err = parseItem()
 		}
 
-		if err := p.parseSymbol(); err != nil {
-			return err
+		if err != nil {
+			if !p.lenient || p.reader.atEOF() {
+				return err
+			}
+			p.addDiagnostic(err)
+			p.reader.err = nil
+			p.skipToMarker()
+			if skipItem != nil {
+				skipItem()
+			}
 		}
 	}
 }
 
+// skipToMarker skips the bytes until the next byte is 0xfe or 0xff.
+func (p *parser) skipToMarker() {
+	for {
+		next, err := p.reader.raw.Peek(1)
+		if err != nil || next[0] == 0xfe || next[0] == 0xff {
+			return
+		}
+		p.reader.readByte()
+	}
+}
+
+// addDiagnostic records the error with the current position of the parser.
+func (p *parser) addDiagnostic(err error) {
+	p.Diagnostics = append(p.Diagnostics, p.parseError(err).(*ParseError))
+}
+
 func (p *parser) parseSymbol() error {
+	symbol, err := p.readSymbol()
+	if err != nil {
+		return err
+	}
+	p.Symbols = append(p.Symbols, symbol)
+	return nil
+}
+
+// readSymbol reads the symbol following the 0xfe marker.
+func (p *parser) readSymbol() (Symbol, error) {
 	symbol := Symbol{}
-	symbol.Kind = SymKind(p.reader.readByte())
+	if p.isGo17() {
+		symbol.Kind = p.Header.symKindOf(p.Format, p.reader.readVarint())
+	} else {
+		symbol.Kind = p.Header.symKindOf(p.Format, int64(p.reader.readByte()))
+	}
 	symbol.IDIndex = p.reader.readVarint()
+	p.symbolName = p.referenceName(symbol.IDIndex)
 
 	flags := p.reader.readVarint()
 	symbol.DupOK = flags&0x1 != 0
@@ -586,12 +1010,12 @@ func (p *parser) parseSymbol() error {
 	symbol.DataAddr = DataAddr{Size: dataSize, Offset: p.associatedDataSize}
 	p.associatedDataSize += dataSize
 
-	numRelocs := p.reader.readVarint()
-	for i := 0; i < int(numRelocs); i++ {
+	numRelocs := p.reader.readCount("number of relocations", p.reader.limit().MaxRelocations)
+	for i := int64(0); i < numRelocs && p.reader.err == nil; i++ {
 		reloc := Relocation{}
 		reloc.Offset = p.reader.readVarint()
 		reloc.Size = p.reader.readVarint()
-		reloc.Type = RelocType(p.reader.readVarint())
+		reloc.Type = p.Header.relocTypeOf(p.Format, p.reader.readVarint())
 		reloc.Add = p.reader.readVarint()
 		reloc.IDIndex = p.reader.readVarint()
 
@@ -599,79 +1023,180 @@ func (p *parser) parseSymbol() error {
 	}
 
 	if symbol.Kind == STEXT {
-		if err := p.skipSTEXTFields(); err != nil {
-			return err
+		fields, err := p.parseSTEXTFields()
+		if err != nil {
+			return Symbol{}, err
 		}
+		symbol.Func = fields
 	}
 
-	p.Symbols = append(p.Symbols, symbol)
-	return p.reader.err
+	return symbol, p.reader.err
 }
 
-func (p *parser) skipSTEXTFields() error {
-	_ = p.reader.readVarint() // Args
-	_ = p.reader.readVarint() // Frame
-	_ = p.reader.readVarint() // Flags
-	_ = p.reader.readVarint() // NoSplit
+func (p *parser) parseSTEXTFields() (*StextFields, error) {
+	fields := &StextFields{}
+	fields.Args = p.reader.readVarint()
+	fields.Frame = p.reader.readVarint()
+	fields.NoSplit = p.reader.readVarint() != 0
+
+	flags := p.reader.readVarint()
+	fields.Leaf = flags&0x1 != 0
+	fields.CFunc = (flags>>1)&0x1 != 0
+	fields.TypeMethod = (flags>>2)&0x1 != 0
+	fields.SharedFunc = (flags>>3)&0x1 != 0
 
 	numLocals := p.reader.readVarint()
-	for i := 0; i < int(numLocals); i++ {
-		_ = p.reader.readVarint() // sym
-		_ = p.reader.readVarint() // offset
-		_ = p.reader.readVarint() // type
-		_ = p.reader.readVarint() // go type
+	for i := int64(0); i < numLocals && p.reader.err == nil; i++ {
+		local := Local{}
+		local.AsymIndex = p.reader.readVarint()
+		local.Offset = p.reader.readVarint()
+		local.Type = p.reader.readVarint()
+		local.GotypeIndex = p.reader.readVarint()
+
+		fields.Local = append(fields.Local, local)
 	}
 
-	pcspSize := p.reader.readVarint()
-	p.associatedDataSize += pcspSize
-
-	pcFileSize := p.reader.readVarint()
-	p.associatedDataSize += pcFileSize
-
-	pcLineSize := p.reader.readVarint()
-	p.associatedDataSize += pcLineSize
-
-	pcInlineSize := p.reader.readVarint()
-	p.associatedDataSize += pcInlineSize
+	fields.PCSP = p.readDataAddr()
+	fields.PCFile = p.readDataAddr()
+	fields.PCLine = p.readDataAddr()
+	if !p.isGo17() {
+		fields.PCInline = p.readDataAddr()
+	}
 
 	numPCData := p.reader.readVarint()
-	for i := 0; i < int(numPCData); i++ {
-		pcDataSize := p.reader.readVarint()
-		p.associatedDataSize += pcDataSize
+	for i := int64(0); i < numPCData && p.reader.err == nil; i++ {
+		fields.PCData = append(fields.PCData, p.readDataAddr())
 	}
 
 	numFuncData := p.reader.readVarint()
-	for i := 0; i < int(numFuncData); i++ {
-		_ = p.reader.readVarint() // func data index
+	for i := int64(0); i < numFuncData && p.reader.err == nil; i++ {
+		fields.FuncDataIndex = append(fields.FuncDataIndex, p.reader.readVarint())
 	}
-	for i := 0; i < int(numFuncData); i++ {
-		_ = p.reader.readVarint() // func offset
+	for i := int64(0); i < numFuncData && p.reader.err == nil; i++ {
+		fields.FuncDataOffset = append(fields.FuncDataOffset, p.reader.readVarint())
 	}
 
 	numFiles := p.reader.readVarint()
-	for i := 0; i < int(numFiles); i++ {
-		_ = p.reader.readVarint() // file index
+	for i := int64(0); i < numFiles && p.reader.err == nil; i++ {
+		fields.FileIndex = append(fields.FileIndex, p.reader.readVarint())
+	}
+
+	if p.isGo17() {
+		return fields, p.reader.err
 	}
 
 	numInlineTrees := p.reader.readVarint()
-	for i := 0; i < int(numInlineTrees); i++ {
-		_ = p.reader.readVarint() // parent
-		_ = p.reader.readVarint() // file
-		_ = p.reader.readVarint() // line
-		_ = p.reader.readVarint() // func
+	for i := int64(0); i < numInlineTrees && p.reader.err == nil; i++ {
+		call := InlinedCall{}
+		call.Parent = p.reader.readVarint()
+		call.FileIndex = p.reader.readVarint()
+		call.Line = p.reader.readVarint()
+		call.FuncIndex = p.reader.readVarint()
+		if p.reader.err != nil {
+			return nil, p.reader.err
+		}
+
+		if call.FileIndex < 0 || call.FileIndex >= int64(len(p.SymbolReferences)) {
+			return nil, fmt.Errorf("invalid file index of the inlined call: %d", call.FileIndex)
+		}
+		call.File = strings.TrimPrefix(p.SymbolReferences[call.FileIndex].Name, fileSymbolPrefix)
+
+		if call.FuncIndex < 0 || call.FuncIndex >= int64(len(p.SymbolReferences)) {
+			return nil, fmt.Errorf("invalid func index of the inlined call: %d", call.FuncIndex)
+		}
+		call.Func = p.SymbolReferences[call.FuncIndex].Name
+
+		fields.InlineTree = append(fields.InlineTree, call)
 	}
 
-	return p.reader.err
+	return fields, p.reader.err
+}
+
+func (p *parser) isGo17() bool {
+	return p.Format == FormatGo17
+}
+
+// readDataAddr reads the size of the region which follows the regions associated so far.
+func (p *parser) readDataAddr() DataAddr {
+	size := p.reader.readVarint()
+	addr := DataAddr{Size: size, Offset: p.associatedDataSize}
+	p.associatedDataSize += size
+	return addr
+}
+
+// checkHeader compares the lengths in the header with the actual lengths of the parsed symbols.
+func (p *parser) checkHeader() error {
+	return p.checkLengths(p.countLengths())
+}
+
+// checkLengths compares the lengths in the header with the given lengths, except the data length.
+// The data length is compared with the total size of the regions associated with the symbols.
+func (p *parser) checkLengths(actual Header) error {
+	actual.DataLength = p.associatedDataSize
+
+	for _, length := range []struct {
+		name             string
+		header, computed int64
+	}{
+		{"data length", p.Header.DataLength, actual.DataLength},
+		{"number of relocations", p.Header.NumRelocations, actual.NumRelocations},
+		{"number of pcdata", p.Header.NumPCData, actual.NumPCData},
+		{"number of locals", p.Header.NumLocals, actual.NumLocals},
+		{"number of funcdata", p.Header.NumFuncData, actual.NumFuncData},
+		{"number of files", p.Header.NumFiles, actual.NumFiles},
+	} {
+		if length.header != length.computed {
+			return fmt.Errorf("%s mismatch: header says %d, but symbols have %d", length.name, length.header, length.computed)
+		}
+	}
+	return nil
+}
+
+// countLengths returns the header whose lengths, except the data length, are computed from the symbols.
+func (f *File) countLengths() Header {
+	var header Header
+	for i := range f.Symbols {
+		header.addLengths(&f.Symbols[i])
+	}
+	return header
+}
+
+// addLengths adds the lengths of the symbol to the header.
+func (h *Header) addLengths(symbol *Symbol) {
+	h.NumRelocations += int64(len(symbol.Relocations))
+	if symbol.Func == nil {
+		return
+	}
+	h.NumPCData += int64(len(symbol.Func.PCData))
+	h.NumLocals += int64(len(symbol.Func.Local))
+	h.NumFuncData += int64(len(symbol.Func.FuncDataIndex))
+	h.NumFiles += int64(len(symbol.Func.FileIndex))
+}
+
+// setLengths sets the lengths in the header for the formats which do not have them.
+func (f *File) setLengths() {
+	lengths := f.countLengths()
+	f.Header.DataLength = int64(len(f.DataBlock))
+	f.Header.NumRelocations = lengths.NumRelocations
+	f.Header.NumPCData = lengths.NumPCData
+	f.Header.NumLocals = lengths.NumLocals
+	f.Header.NumFuncData = lengths.NumFuncData
+	f.Header.NumFiles = lengths.NumFiles
 }
 
 func (p *parser) skipFooter() error {
-	buff := make([]byte, len(magicFooter))
+	footer := magicFooter
+	if isLegacyMagic(p.magic) {
+		footer = footerFor(p.magic)
+	}
+
+	buff := make([]byte, len(footer))
 	_ = p.reader.read(buff)
 	if p.reader.err != nil {
 		return p.reader.err
 	}
 
-	if !reflect.DeepEqual(buff, magicFooter) {
+	if !reflect.DeepEqual(buff, footer) {
 		return fmt.Errorf("invalid footer: %#x", buff)
 	}
 	return nil
@@ -684,12 +1209,29 @@ type readerWithCounter struct {
 	raw          *bufio.Reader
 	numReadBytes int64
 	err          error
+	limits       Limits
+}
+
+// atEOF returns true if the reader reached the end of the file.
+func (r *readerWithCounter) atEOF() bool {
+	return r.err == io.EOF || r.err == io.ErrUnexpectedEOF
+}
+
+// limit returns the limits of the reader. The zero fields are the default values.
+func (r *readerWithCounter) limit() Limits {
+	return r.limits.withDefaults()
 }
 
 func (r *readerWithCounter) readVarint() int64 {
 	var value uint64
 	var shift uint64
-	for {
+	maxLength := r.limit().MaxVarintLength
+	for numBytes := 1; ; numBytes++ {
+		if numBytes > maxLength {
+			r.err = fmt.Errorf("too long varint: more than %d bytes", maxLength)
+			return 0
+		}
+
 		b := r.readByte()
 		if r.err != nil {
 			return 0
@@ -705,22 +1247,57 @@ func (r *readerWithCounter) readVarint() int64 {
 }
 
 func (r *readerWithCounter) readString() string {
-	len := r.readVarint()
+	return string(r.readBytes("string length", r.limit().MaxStringLength))
+}
+
+// readBytes reads the length and that many bytes. The length must not exceed the limit.
+func (r *readerWithCounter) readBytes(name string, limit int64) []byte {
+	length := r.readCount(name, limit)
 	if r.err != nil {
-		return ""
+		return nil
 	}
+	return r.readN(length)
+}
 
-	buff := make([]byte, len)
-	numRead := 0
-	for numRead != int(len) {
-		n := r.read(buff[numRead:])
-		if r.err != nil {
-			return ""
-		}
-		numRead += n
+// readCount reads the length or the number of the elements. The value must not exceed the limit.
+func (r *readerWithCounter) readCount(name string, limit int64) int64 {
+	count := r.readVarint()
+	if r.err != nil {
+		return 0
+	}
+	if err := checkLength(name, count, limit); err != nil {
+		r.err = err
+		return 0
+	}
+	return count
+}
+
+// readN reads n bytes. The buffer grows as the bytes are read, so the large n does not allocate
+// the memory unless the file has that many bytes.
+func (r *readerWithCounter) readN(n int64) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	buff := &bytes.Buffer{}
+	copied, err := io.CopyN(buff, r.raw, n)
+	r.numReadBytes += copied
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	return buff.Bytes()
+}
+
+// skip discards n bytes.
+func (r *readerWithCounter) skip(n int64) {
+	if r.err != nil {
+		return
 	}
 
-	return string(buff)
+	skipped, err := io.CopyN(ioutil.Discard, r.raw, n)
+	r.numReadBytes += skipped
+	r.err = err
 }
 
 func (r *readerWithCounter) readByte() (b byte) {
@@ -733,12 +1310,13 @@ func (r *readerWithCounter) readByte() (b byte) {
 	return
 }
 
+// read reads exactly len(p) bytes. io.ErrUnexpectedEOF is set if the file ends in the middle.
 func (r *readerWithCounter) read(p []byte) (n int) {
 	if r.err != nil {
 		return
 	}
 
-	n, r.err = r.raw.Read(p)
+	n, r.err = io.ReadFull(r.raw, p)
 	r.numReadBytes += int64(n)
 	return
 }
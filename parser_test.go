@@ -3,11 +3,74 @@ package goobj
 import (
 	"bufio"
 	"bytes"
+	"io/ioutil"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
+func TestParseBytes(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	file, err := ParseBytes(data)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := parseFileForTesting(t, helloworldObjPath)
+	if !reflect.DeepEqual(expected, file) {
+		t.Errorf("the parsed file should be same as the one parsed from os.File")
+	}
+}
+
+func TestParseReaderAt(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	file, err := ParseReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := parseFileForTesting(t, helloworldObjPath)
+	if !reflect.DeepEqual(expected, file) {
+		t.Errorf("the parsed file should be same as the one parsed from os.File")
+	}
+}
+
+func TestParseReader_OneByteReader(t *testing.T) {
+	for _, path := range []string{helloworldObjPath, helloworldIndexedObjPath} {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+
+		file, err := ParseReader(iotest.OneByteReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatalf("[%s] error should be nil, but %v", path, err)
+		}
+		expected := parseFileForTesting(t, path)
+		if !reflect.DeepEqual(expected, file) {
+			t.Errorf("[%s] the parsed file should be same as the one parsed from os.File", path)
+		}
+	}
+}
+
+func TestParseReaderAt_Truncated(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	if _, err := ParseReaderAt(bytes.NewReader(data), int64(len(data))-1); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
 func TestParser_skipHeader(t *testing.T) {
 	for i, testData := range []struct {
 		in       string
@@ -52,16 +115,20 @@ func TestParser_checkVersion_NotSupportedVersion(t *testing.T) {
 	}
 }
 
-func TestParser_skipDependencies(t *testing.T) {
-	p := newParser(bufio.NewReader(strings.NewReader("\x01\x00")))
-	if err := p.skipDependencies(); err != nil {
+func TestParser_parseDependencies(t *testing.T) {
+	p := newParser(bufio.NewReader(strings.NewReader("\x0afmt.a\x08os.a\x00")))
+	if err := p.parseDependencies(); err != nil {
 		t.Errorf("error should be nil")
 	}
+	expected := []string{"fmt.a", "os.a"}
+	if !reflect.DeepEqual(expected, p.Imports) {
+		t.Errorf("imports should be %v, but %v", expected, p.Imports)
+	}
 }
 
-func TestParser_skipDependencies_EmptyInput(t *testing.T) {
+func TestParser_parseDependencies_EmptyInput(t *testing.T) {
 	p := newParser(bufio.NewReader(strings.NewReader("")))
-	if err := p.skipDependencies(); err == nil {
+	if err := p.parseDependencies(); err == nil {
 		t.Errorf("error should not be nil")
 	}
 }
@@ -109,6 +176,18 @@ func TestParser_parseData(t *testing.T) {
 	}
 }
 
+func TestParser_parseData_Header(t *testing.T) {
+	p := newParser(bufio.NewReader(strings.NewReader("\x02\x02\x04\x06\x08\x0aa")))
+	err := p.parseData()
+	if err != nil {
+		t.Errorf("error should be nil")
+	}
+	expected := Header{DataLength: 1, NumRelocations: 1, NumPCData: 2, NumLocals: 3, NumFuncData: 4, NumFiles: 5}
+	if !reflect.DeepEqual(expected, p.Header) {
+		t.Errorf("the header should be %+v, but %+v", expected, p.Header)
+	}
+}
+
 func TestParser_parseData_128KBData(t *testing.T) {
 	dataLength := "\x80\x80\x10" // 128KB
 	data := strings.Repeat("0123456789abcdef", 8*1024)
@@ -193,15 +272,108 @@ func TestParser_parseSymbol_EmptyInput(t *testing.T) {
 	}
 }
 
-func TestParser_skipSTEXTFields(t *testing.T) {
+func TestParser_parseSTEXTFields(t *testing.T) {
 	in := "\x00\x00\x00\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00\x02\x00\x02\x00\x00\x02\x00\x02\x00\x00\x00\x00"
 	p := newParser(bufio.NewReader(strings.NewReader(in)))
-	err := p.skipSTEXTFields()
+	p.SymbolReferences = []SymbolReference{{}}
+	_, err := p.parseSTEXTFields()
 	if err != nil {
 		t.Errorf("error should be nil")
 	}
 }
 
+func TestParser_parseSTEXTFields_InlineTree(t *testing.T) {
+	in := "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00" +
+		"\x04\x01\x02\x14\x04\x00\x02\x28\x06"
+	p := newParser(bufio.NewReader(strings.NewReader(in)))
+	p.SymbolReferences = []SymbolReference{{}, {Name: "gofile../a.go"}, {Name: "f"}, {Name: "g"}}
+	actual, err := p.parseSTEXTFields()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	expected := []InlinedCall{
+		{Parent: -1, File: "/a.go", FileIndex: 1, Line: 10, Func: "f", FuncIndex: 2},
+		{Parent: 0, File: "/a.go", FileIndex: 1, Line: 20, Func: "g", FuncIndex: 3},
+	}
+	if !reflect.DeepEqual(expected, actual.InlineTree) {
+		t.Errorf("the inline tree should be %+v, but %+v", expected, actual.InlineTree)
+	}
+}
+
+func TestParser_parseSTEXTFields_InvalidInlineTreeIndex(t *testing.T) {
+	in := "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00" +
+		"\x02\x01\x04\x14\x00"
+	p := newParser(bufio.NewReader(strings.NewReader(in)))
+	p.SymbolReferences = []SymbolReference{{}}
+	if _, err := p.parseSTEXTFields(); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestParser_parseSTEXTFields_Values(t *testing.T) {
+	in := "\x04\x06\x02\x0a" + // args, frame, nosplit, flags
+		"\x02\x02\x04\x02\x06" + // locals
+		"\x02\x04\x06\x08" + // pcsp, pcfile, pcline, pcinline
+		"\x02\x0a" + // pcdata
+		"\x02\x08\x10" + // funcdata
+		"\x04\x02\x04" + // files
+		"\x00" // inline tree
+	p := newParser(bufio.NewReader(strings.NewReader(in)))
+	p.associatedDataSize = 1
+	actual, err := p.parseSTEXTFields()
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	expected := &StextFields{
+		Args:           2,
+		Frame:          3,
+		Leaf:           true,
+		CFunc:          false,
+		TypeMethod:     true,
+		SharedFunc:     false,
+		NoSplit:        true,
+		Local:          []Local{{AsymIndex: 1, Offset: 2, Type: 1, GotypeIndex: 3}},
+		PCSP:           DataAddr{Size: 1, Offset: 1},
+		PCFile:         DataAddr{Size: 2, Offset: 2},
+		PCLine:         DataAddr{Size: 3, Offset: 4},
+		PCInline:       DataAddr{Size: 4, Offset: 7},
+		PCData:         []DataAddr{{Size: 5, Offset: 11}},
+		FuncDataIndex:  []int64{4},
+		FuncDataOffset: []int64{8},
+		FileIndex:      []int64{1, 2},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("the fields should be %+v, but %+v", expected, actual)
+	}
+	if p.associatedDataSize != 16 {
+		t.Errorf("the associatedDataSize should be 16, but %d", p.associatedDataSize)
+	}
+}
+
+func TestParser_checkHeader(t *testing.T) {
+	p := newParser(bufio.NewReader(strings.NewReader("")))
+	p.Header = Header{DataLength: 3, NumRelocations: 1, NumPCData: 1, NumLocals: 1, NumFuncData: 2, NumFiles: 1}
+	p.associatedDataSize = 3
+	p.Symbols = []Symbol{
+		{Relocations: []Relocation{{}}},
+		{Func: &StextFields{PCData: []DataAddr{{}}, Local: []Local{{}}, FuncDataIndex: []int64{0, 0}, FileIndex: []int64{0}}},
+	}
+	if err := p.checkHeader(); err != nil {
+		t.Errorf("error should be nil, but %v", err)
+	}
+}
+
+func TestParser_checkHeader_Mismatch(t *testing.T) {
+	p := newParser(bufio.NewReader(strings.NewReader("")))
+	p.Header = Header{NumRelocations: 2}
+	p.Symbols = []Symbol{{Relocations: []Relocation{{}}}}
+	if err := p.checkHeader(); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
 func TestParser_skipFooter(t *testing.T) {
 	p := newParser(bufio.NewReader(bytes.NewReader(magicFooter)))
 	err := p.skipFooter()
@@ -274,6 +446,14 @@ func TestReaderWithCounter_readString(t *testing.T) {
 	}
 }
 
+func TestReaderWithCounter_readString_NegativeLength(t *testing.T) {
+	reader := readerWithCounter{raw: bufio.NewReader(strings.NewReader("\x01"))}
+	_ = reader.readString()
+	if reader.err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
 func TestReaderWithCounter_readString_TooShortString(t *testing.T) {
 	reader := readerWithCounter{raw: bufio.NewReader(strings.NewReader("\x02"))}
 	_ = reader.readString()
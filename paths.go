@@ -0,0 +1,70 @@
+package goobj
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RewritePaths replaces the prefix `from` of the source file paths with `to`, like the -trimpath option of the compiler.
+// If `to` is empty, the prefix is removed and the path becomes relative.
+//
+// The paths are the names of the gofile.. symbols in the reference table, which the pcln tables, the inlining trees and
+// the DWARF symbols refer to. Returns the number of the rewritten references.
+// The error is returned if `from` is empty or no path starts with `from`.
+func (f *File) RewritePaths(from, to string) (int, error) {
+	if from == "" {
+		return 0, errors.New("the path prefix to be rewritten is empty")
+	}
+	if from != "/" {
+		from = strings.TrimSuffix(from, "/")
+	}
+
+	numRewritten := 0
+	for i, reference := range f.SymbolReferences {
+		if !strings.HasPrefix(reference.Name, fileSymbolPrefix) {
+			continue
+		}
+
+		path, ok := rewritePath(strings.TrimPrefix(reference.Name, fileSymbolPrefix), from, to)
+		if ok {
+			f.SymbolReferences[i].Name = fileSymbolPrefix + path
+			numRewritten++
+		}
+	}
+	if numRewritten == 0 {
+		return 0, fmt.Errorf("no source file path starts with %s", from)
+	}
+
+	for _, symbol := range f.Symbols {
+		if symbol.Func == nil {
+			continue
+		}
+		for i, call := range symbol.Func.InlineTree {
+			if call.FileIndex > 0 && call.FileIndex < int64(len(f.SymbolReferences)) {
+				symbol.Func.InlineTree[i].File = strings.TrimPrefix(f.SymbolReferences[call.FileIndex].Name, fileSymbolPrefix)
+			}
+		}
+	}
+	return numRewritten, nil
+}
+
+func rewritePath(path, from, to string) (string, bool) {
+	if path == from {
+		return to, true
+	}
+
+	prefix := from
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if !strings.HasPrefix(path, prefix) {
+		return path, false
+	}
+
+	rest := path[len(prefix):]
+	if to == "" {
+		return rest, true
+	}
+	return strings.TrimSuffix(to, "/") + "/" + rest, true
+}
@@ -0,0 +1,66 @@
+package goobj
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFile_RewritePaths(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	const from = "/Users/yagami/go/src/github.com/ks888/goobj"
+	if num, err := file.RewritePaths(from, "/build"); err != nil || num != 1 {
+		t.Errorf("the number of rewritten paths should be 1, but %d (%v)", num, err)
+	}
+
+	buff := &bytes.Buffer{}
+	if _, err := file.WriteTo(buff); err != nil {
+		t.Fatalf("failed to write the file: %v", err)
+	}
+	reparsed, err := ParseBytes(buff.Bytes())
+	if err != nil {
+		t.Fatalf("failed to parse the rewritten file: %v", err)
+	}
+
+	filename, _, err := reparsed.LineForPC(&reparsed.Symbols[0], 0)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if filename != "/build/cmd/readgoobj/testdata/helloworld.go" {
+		t.Errorf("the path should be rewritten, but %s", filename)
+	}
+}
+
+func TestFile_RewritePaths_InvalidPrefix(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	for i, from := range []string{"", "/not/found"} {
+		if _, err := file.RewritePaths(from, "/build"); err == nil {
+			t.Errorf("[%d] error should not be nil", i)
+		}
+	}
+	filename, _, err := file.LineForPC(&file.Symbols[0], 0)
+	if err != nil || !strings.HasPrefix(filename, "/Users/yagami/") {
+		t.Errorf("the path should not be rewritten, but %s (%v)", filename, err)
+	}
+}
+
+func TestRewritePath(t *testing.T) {
+	for i, testData := range []struct {
+		path, from, to string
+		expected       string
+		rewritten      bool
+	}{
+		{path: "/build/ws/a.go", from: "/build/ws", to: "", expected: "a.go", rewritten: true},
+		{path: "/build/ws/a.go", from: "/build/ws/", to: "/src", expected: "/src/a.go", rewritten: true},
+		{path: "/build/ws/a.go", from: "/build/ws", to: "/src/", expected: "/src/a.go", rewritten: true},
+		{path: "/build/ws", from: "/build/ws", to: "/src", expected: "/src", rewritten: true},
+		{path: "/build/wsx/a.go", from: "/build/ws", to: "", expected: "/build/wsx/a.go", rewritten: false},
+		{path: "<autogenerated>", from: "/build/ws", to: "", expected: "<autogenerated>", rewritten: false},
+		{path: "/a.go", from: "/", to: "", expected: "a.go", rewritten: true},
+	} {
+		actual, rewritten := rewritePath(testData.path, testData.from, testData.to)
+		if actual != testData.expected || rewritten != testData.rewritten {
+			t.Errorf("[%d] path should be %s (%v), but %s (%v)", i, testData.expected, testData.rewritten, actual, rewritten)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package goobj
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// PCValueRange represents the value which is valid while the pc is in the [StartPC, EndPC) range.
+// The pc is the offset from the beginning of the function.
+type PCValueRange struct {
+	StartPC, EndPC int64
+	Value          int64
+}
+
+// PCValueIterator walks the (pc, value) runs of the pcvalue table such as PCSP and PCLine.
+//
+// The table is the sequence of the pairs of the value delta (zigzag varint) and
+// the pc delta (uvarint, in the unit of the pc quantum). It ends with the zero value delta,
+// except for the first pair.
+type PCValueIterator struct {
+	data    []byte
+	quantum int64
+	pc      int64
+	value   int64
+	first   bool
+	current PCValueRange
+	err     error
+}
+
+// NewPCValueIterator returns the iterator of the given pcvalue table.
+// The quantum is the minimum instruction size of the architecture. See PCQuantum.
+func NewPCValueIterator(data []byte, quantum int64) *PCValueIterator {
+	return &PCValueIterator{data: data, quantum: quantum, value: -1, first: true}
+}
+
+// Next advances the iterator to the next range. It returns false when the table ends or an error happens.
+func (it *PCValueIterator) Next() bool {
+	if it.err != nil || len(it.data) == 0 {
+		return false
+	}
+
+	uvdelta, n := binary.Uvarint(it.data)
+	if n <= 0 {
+		it.err = errors.New("invalid value delta in the pcvalue table")
+		return false
+	}
+	if uvdelta == 0 && !it.first {
+		it.data = nil
+		return false
+	}
+	it.data = it.data[n:]
+
+	pcdelta, n := binary.Uvarint(it.data)
+	if n <= 0 {
+		it.err = errors.New("invalid pc delta in the pcvalue table")
+		return false
+	}
+	it.data = it.data[n:]
+
+	it.value += zigzagDecode(uvdelta)
+	it.current = PCValueRange{StartPC: it.pc, EndPC: it.pc + int64(pcdelta)*it.quantum, Value: it.value}
+	it.pc = it.current.EndPC
+	it.first = false
+	return true
+}
+
+// Range returns the current range.
+func (it *PCValueIterator) Range() PCValueRange {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *PCValueIterator) Err() error {
+	return it.err
+}
+
+// DecodePCValue decodes the whole pcvalue table.
+func DecodePCValue(data []byte, quantum int64) ([]PCValueRange, error) {
+	var ranges []PCValueRange
+	it := NewPCValueIterator(data, quantum)
+	for it.Next() {
+		ranges = append(ranges, it.Range())
+	}
+	return ranges, it.Err()
+}
+
+// EncodePCValue encodes the ranges to the pcvalue table. The ranges must be contiguous and start from the pc 0.
+// The quantum is the minimum instruction size of the architecture. See PCQuantum.
+func EncodePCValue(ranges []PCValueRange, quantum int64) ([]byte, error) {
+	var buff []byte
+	pc, value := int64(0), int64(-1)
+	for i, r := range ranges {
+		if r.StartPC != pc || r.EndPC < r.StartPC || (r.EndPC-r.StartPC)%quantum != 0 {
+			return nil, fmt.Errorf("invalid range: %+v", r)
+		}
+		if i != 0 && r.Value == value {
+			return nil, fmt.Errorf("the value is same as the previous range: %+v", r)
+		}
+
+		buff = appendUvarint(buff, zigzagEncode(r.Value-value))
+		buff = appendUvarint(buff, uint64((r.EndPC-r.StartPC)/quantum))
+		pc, value = r.EndPC, r.Value
+	}
+	if len(ranges) > 0 {
+		buff = append(buff, 0)
+	}
+	return buff, nil
+}
+
+func appendUvarint(buff []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buff = append(buff, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buff, byte(v))
+}
+
+// PCQuantum returns the minimum instruction size of the given architecture (GOARCH value).
+// The pc delta of the pcvalue table is in this unit.
+func PCQuantum(goarch string) int64 {
+	switch goarch {
+	case "s390x":
+		return 2
+	case "arm", "arm64", "loong64", "mips", "mipsle", "mips64", "mips64le", "ppc64", "ppc64le", "riscv64", "sparc64":
+		return 4
+	default:
+		// 386, amd64 and wasm
+		return 1
+	}
+}
+
+// PCSP decodes the table which maps the pc to the stack pointer offset (the frame size at the pc).
+func (f *File) PCSP(symbol *Symbol) ([]PCValueRange, error) {
+	if symbol.Func == nil {
+		return nil, errNotFunction
+	}
+	return f.decodePCValue(symbol.Func.PCSP)
+}
+
+// PCFile decodes the table which maps the pc to the index of the symbol's FileIndex list.
+func (f *File) PCFile(symbol *Symbol) ([]PCValueRange, error) {
+	if symbol.Func == nil {
+		return nil, errNotFunction
+	}
+	return f.decodePCValue(symbol.Func.PCFile)
+}
+
+// PCLine decodes the table which maps the pc to the line number.
+func (f *File) PCLine(symbol *Symbol) ([]PCValueRange, error) {
+	if symbol.Func == nil {
+		return nil, errNotFunction
+	}
+	return f.decodePCValue(symbol.Func.PCLine)
+}
+
+// PCInline decodes the table which maps the pc to the index of the symbol's inline tree (-1 if not inlined).
+func (f *File) PCInline(symbol *Symbol) ([]PCValueRange, error) {
+	if symbol.Func == nil {
+		return nil, errNotFunction
+	}
+	return f.decodePCValue(symbol.Func.PCInline)
+}
+
+// PCData decodes the index-th pcdata table.
+func (f *File) PCData(symbol *Symbol, index int) ([]PCValueRange, error) {
+	if symbol.Func == nil {
+		return nil, errNotFunction
+	}
+	if index < 0 || index >= len(symbol.Func.PCData) {
+		return nil, fmt.Errorf("pcdata index out of range: %d", index)
+	}
+	return f.decodePCValue(symbol.Func.PCData[index])
+}
+
+var errNotFunction = errors.New("not a STEXT symbol")
+
+func (f *File) decodePCValue(addr DataAddr) ([]PCValueRange, error) {
+	if addr.Offset < 0 || addr.Size < 0 || addr.Offset+addr.Size > int64(len(f.DataBlock)) {
+		return nil, fmt.Errorf("pcvalue table out of the data block: %+v", addr)
+	}
+	return DecodePCValue(f.DataBlock[addr.Offset:addr.Offset+addr.Size], f.pcQuantum())
+}
+
+// pcQuantum returns the pc quantum of the architecture the object file is built for.
+func (f *File) pcQuantum() int64 {
+	return f.Header.PCQuantum()
+}
@@ -0,0 +1,135 @@
+package goobj
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var helloworldObjPath = filepath.Join("cmd", "readgoobj", "testdata", "helloworld.o")
+
+func parseFileForTesting(t *testing.T, path string) *File {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	file, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	return file
+}
+
+func TestDecodePCValue(t *testing.T) {
+	for i, testData := range []struct {
+		in       string
+		quantum  int64
+		expected []PCValueRange
+	}{
+		{in: "\x02\x05\x10\x03\x00", quantum: 1, expected: []PCValueRange{{0, 5, 0}, {5, 8, 8}}},
+		{in: "\x02\x05\x10\x03\x00", quantum: 4, expected: []PCValueRange{{0, 20, 0}, {20, 32, 8}}},
+		{in: "\x00\x05\x00", quantum: 1, expected: []PCValueRange{{0, 5, -1}}},
+		{in: "\x02\x05\x03\x01", quantum: 1, expected: []PCValueRange{{0, 5, 0}, {5, 6, -2}}},
+		{in: "", quantum: 1, expected: nil},
+	} {
+		actual, err := DecodePCValue([]byte(testData.in), testData.quantum)
+		if err != nil {
+			t.Errorf("[%d] error should be nil, but %v", i, err)
+		}
+		if !reflect.DeepEqual(testData.expected, actual) {
+			t.Errorf("[%d] ranges should be %+v, but %+v", i, testData.expected, actual)
+		}
+	}
+}
+
+func TestDecodePCValue_Truncated(t *testing.T) {
+	if _, err := DecodePCValue([]byte("\x02"), 1); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_PCSP(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	mainFunc := &file.Symbols[0]
+
+	actual, err := file.PCSP(mainFunc)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := []PCValueRange{{0, 19, 0}, {19, 102, 72}, {102, 110, 0}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("ranges should be %+v, but %+v", expected, actual)
+	}
+}
+
+func TestFile_PCLine(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	mainFunc := &file.Symbols[0]
+
+	actual, err := file.PCLine(mainFunc)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	expected := []PCValueRange{{0, 29, 7}, {29, 93, 8}, {93, 103, 9}, {103, 110, 7}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("ranges should be %+v, but %+v", expected, actual)
+	}
+}
+
+func TestFile_PCData_OutOfRange(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	if _, err := file.PCData(&file.Symbols[0], 1); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_PCSP_NotFunction(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	if _, err := file.PCSP(&file.Symbols[2]); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestPCQuantum(t *testing.T) {
+	for goarch, expected := range map[string]int64{"amd64": 1, "386": 1, "s390x": 2, "arm64": 4, "ppc64le": 4} {
+		if actual := PCQuantum(goarch); actual != expected {
+			t.Errorf("[%s] quantum should be %d, but %d", goarch, expected, actual)
+		}
+	}
+}
+
+func TestEncodePCValue(t *testing.T) {
+	for i, testData := range []struct {
+		in       []PCValueRange
+		quantum  int64
+		expected string
+	}{
+		{in: []PCValueRange{{0, 5, 0}, {5, 8, 8}}, quantum: 1, expected: "\x02\x05\x10\x03\x00"},
+		{in: []PCValueRange{{0, 20, 0}, {20, 32, 8}}, quantum: 4, expected: "\x02\x05\x10\x03\x00"},
+		{in: []PCValueRange{{0, 5, -1}}, quantum: 1, expected: "\x00\x05\x00"},
+		{in: nil, quantum: 1, expected: ""},
+	} {
+		actual, err := EncodePCValue(testData.in, testData.quantum)
+		if err != nil {
+			t.Errorf("[%d] error should be nil, but %v", i, err)
+		}
+		if string(actual) != testData.expected {
+			t.Errorf("[%d] table should be %q, but %q", i, testData.expected, actual)
+		}
+	}
+}
+
+func TestEncodePCValue_InvalidRanges(t *testing.T) {
+	for i, in := range [][]PCValueRange{
+		{{1, 5, 0}},
+		{{0, 5, 0}, {6, 8, 1}},
+		{{0, 5, 0}, {5, 8, 0}},
+	} {
+		if _, err := EncodePCValue(in, 1); err == nil {
+			t.Errorf("[%d] error should not be nil", i)
+		}
+	}
+}
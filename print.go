@@ -7,6 +7,24 @@ import (
 	"strings"
 )
 
+// PrintHeader prints the target platform and the toolchain of the object file.
+func PrintHeader(file *File) {
+	fmt.Println("The object file header:")
+	fmt.Printf(" GOOS: %s\n", file.Header.GOOS)
+	fmt.Printf(" GOARCH: %s\n", file.Header.GOARCH)
+	fmt.Printf(" Go version: %s\n", file.Header.GoVersion)
+	fmt.Printf(" Experiments: %s\n", strings.Join(file.Header.Experiments, ","))
+}
+
+// PrintImports prints the packages the object file depends on.
+func PrintImports(file *File) {
+	fmt.Println("The list of imported packages:")
+
+	for _, imported := range file.Imports {
+		fmt.Printf(" %s\n", imported)
+	}
+}
+
 var symbolHeaderRows = []string{"Offset", "Size", "Type", "DupOK", "Local", "MakeTypeLink", "Name", "Version", "GoType"}
 
 // PrintSymbols prints the symbols in the table format.
@@ -15,8 +33,7 @@ func PrintSymbols(file *File) {
 
 	table := newTable(symbolHeaderRows)
 	for _, symbol := range file.Symbols {
-		ref := file.SymbolReferences[symbol.IDIndex]
-		goType := file.SymbolReferences[symbol.GoTypeIndex]
+		ref, _ := file.Ref(symbol.IDIndex)
 
 		row := []string{
 			fmt.Sprintf("%#x", file.DataBlockPosition+symbol.DataAddr.Offset),
@@ -27,13 +44,80 @@ func PrintSymbols(file *File) {
 			fmt.Sprintf("%v", symbol.Typelink),
 			fmt.Sprintf("%s", ref.Name),
 			fmt.Sprintf("%d", ref.Version),
-			fmt.Sprintf("%s", goType.Name),
+			fmt.Sprintf("%s", symbol.GoType(file)),
 		}
 		table.addRow(row)
 	}
 	table.print()
 }
 
+var inlinedCallHeaderRows = []string{"Index", "Parent", "Func", "File", "Line"}
+
+// PrintInlinedCalls prints the inlining tree of each function in the table format.
+// The name of the inlined function is indented according to its depth in the tree.
+func PrintInlinedCalls(file *File) {
+	for _, symbol := range file.Symbols {
+		if symbol.Func == nil {
+			continue
+		}
+
+		fmt.Printf("The inlining tree of %s:\n", symbol.Name(file))
+
+		table := newTable(inlinedCallHeaderRows)
+		for i, call := range symbol.Func.InlineTree {
+			row := []string{
+				fmt.Sprintf("%d", i),
+				fmt.Sprintf("%d", call.Parent),
+				strings.Repeat("  ", inlineDepth(symbol.Func.InlineTree, i)) + call.Func,
+				call.File,
+				fmt.Sprintf("%d", call.Line),
+			}
+			table.addRow(row)
+		}
+		table.print()
+	}
+}
+
+// inlineDepth returns the number of the ancestors of the specified node.
+func inlineDepth(tree []InlinedCall, index int) (depth int) {
+	for parent := tree[index].Parent; parent >= 0 && parent < int64(len(tree)) && depth < len(tree); parent = tree[parent].Parent {
+		depth++
+	}
+	return
+}
+
+var lineHeaderRows = []string{"Func", "StartPC", "EndPC", "File", "Line"}
+
+// PrintLines prints the source position of each function's instructions in the table format.
+func PrintLines(file *File) error {
+	fmt.Println("The line table:")
+
+	table := newTable(lineHeaderRows)
+	for i, symbol := range file.Symbols {
+		if symbol.Func == nil {
+			continue
+		}
+
+		lineTable, err := file.LineTable(&file.Symbols[i])
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range lineTable {
+			row := []string{
+				symbol.Name(file),
+				fmt.Sprintf("%#x", entry.StartPC),
+				fmt.Sprintf("%#x", entry.EndPC),
+				entry.File,
+				fmt.Sprintf("%d", entry.Line),
+			}
+			table.addRow(row)
+		}
+	}
+	table.print()
+	return nil
+}
+
 type table struct {
 	headers []string
 	rows    [][]string
@@ -28,3 +28,13 @@ func TestTable_writeRowTo(t *testing.T) {
 		t.Errorf("max widths should be %+v, but %+v", expected, actual)
 	}
 }
+
+func TestInlineDepth(t *testing.T) {
+	tree := []InlinedCall{{Parent: -1}, {Parent: 0}, {Parent: 1}, {Parent: -1}}
+	for i, expected := range []int{0, 1, 2, 0} {
+		actual := inlineDepth(tree, i)
+		if expected != actual {
+			t.Errorf("[%d] depth should be %d, but %d", i, expected, actual)
+		}
+	}
+}
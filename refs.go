@@ -0,0 +1,66 @@
+package goobj
+
+import "fmt"
+
+// Ref returns the symbol reference of the index. The index 0 is the nil symbol, whose name is empty.
+// The error is returned if the index is out of range.
+func (f *File) Ref(index int64) (SymbolReference, error) {
+	if index < 0 || index >= int64(len(f.SymbolReferences)) {
+		return SymbolReference{}, fmt.Errorf("symbol reference index out of range: %d", index)
+	}
+	return f.SymbolReferences[index], nil
+}
+
+// referenceName returns the name of the reference. Empty if the index is invalid.
+func (f *File) referenceName(index int64) string {
+	ref, _ := f.Ref(index)
+	return ref.Name
+}
+
+// Name returns the name of the symbol. Empty if the index of the symbol reference is invalid.
+func (symbol *Symbol) Name(f *File) string {
+	return f.referenceName(symbol.IDIndex)
+}
+
+// GoType returns the name of the go type symbol of the symbol. Empty if the symbol has no go type
+// or the index of the symbol reference is invalid.
+func (symbol *Symbol) GoType(f *File) string {
+	return f.referenceName(symbol.GoTypeIndex)
+}
+
+// Target returns the name of the symbol the relocation refers to. Empty if the relocation refers to no symbol
+// or the index of the symbol reference is invalid.
+func (reloc *Relocation) Target(f *File) string {
+	return f.referenceName(reloc.IDIndex)
+}
+
+// SymbolsByName returns the map from the symbol name to the index of the Symbols.
+// If the symbols have the same name, such as the DupOK symbols and the static symbols, the first one is used.
+//
+// The map is built at the first call and cached in the file, so it must not be modified.
+// RenameSymbol rebuilds the map, but the direct changes of Symbols and SymbolReferences are not reflected.
+func (f *File) SymbolsByName() map[string]int {
+	if f.symbolsByName != nil {
+		return f.symbolsByName
+	}
+
+	symbols := make(map[string]int, len(f.Symbols))
+	for i := range f.Symbols {
+		name := f.Symbols[i].Name(f)
+		if _, ok := symbols[name]; name == "" || ok {
+			continue
+		}
+		symbols[name] = i
+	}
+	f.symbolsByName = symbols
+	return symbols
+}
+
+// Lookup returns the symbol of the given name. See SymbolsByName for the symbols of the same name.
+func (f *File) Lookup(name string) (*Symbol, bool) {
+	i, ok := f.SymbolsByName()[name]
+	if !ok {
+		return nil, false
+	}
+	return &f.Symbols[i], true
+}
@@ -0,0 +1,112 @@
+package goobj
+
+import "testing"
+
+func TestFile_Ref(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	ref, err := file.Ref(file.Symbols[0].IDIndex)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if ref.Name != `"".main` {
+		t.Errorf("name should be \"\".main, but %s", ref.Name)
+	}
+
+	if ref, err := file.Ref(0); err != nil || ref != (SymbolReference{}) {
+		t.Errorf("the 1st reference should be the nil symbol: %+v, %v", ref, err)
+	}
+	for _, index := range []int64{-1, int64(len(file.SymbolReferences))} {
+		if _, err := file.Ref(index); err == nil {
+			t.Errorf("error should not be nil: %d", index)
+		}
+	}
+}
+
+func TestSymbol_Name(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	symbols := file.SymbolsByName()
+
+	statictmp := file.Symbols[symbols[`"".statictmp_0`]]
+	if name := statictmp.Name(file); name != `"".statictmp_0` {
+		t.Errorf("name should be \"\".statictmp_0, but %s", name)
+	}
+	if goType := statictmp.GoType(file); goType != "type.string" {
+		t.Errorf("go type should be type.string, but %s", goType)
+	}
+
+	main := file.Symbols[symbols[`"".main`]]
+	if goType := main.GoType(file); goType != "" {
+		t.Errorf("go type should be empty, but %s", goType)
+	}
+	found := false
+	for _, reloc := range main.Relocations {
+		if reloc.Target(file) == "fmt.Println" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("the relocation to fmt.Println should be found")
+	}
+}
+
+func TestSymbol_Name_InvalidIndex(t *testing.T) {
+	file := &File{SymbolReferences: []SymbolReference{{}}}
+	symbol := Symbol{IDIndex: 10, GoTypeIndex: -1}
+	reloc := Relocation{IDIndex: 10}
+	if symbol.Name(file) != "" || symbol.GoType(file) != "" || reloc.Target(file) != "" {
+		t.Errorf("the names should be empty")
+	}
+
+	// not panic
+	file.Symbols = append(file.Symbols, symbol)
+	PrintSymbols(file)
+}
+
+func TestFile_SymbolsByName(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".a`, SRODATA, nil)
+	b.AddSymbol(`"".b`, SRODATA, nil).DupOK = true
+	b.AddSymbol(`"".b`, SRODATA, nil).DupOK = true
+	file := b.Build()
+
+	symbols := file.SymbolsByName()
+	if len(symbols) != 2 || symbols[`"".a`] != 0 || symbols[`"".b`] != 1 {
+		t.Errorf("invalid map: %v", symbols)
+	}
+}
+
+func TestFile_Lookup(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".a`, SRODATA, []byte{1})
+	b.AddSymbol(`go.info."".a`, SDWARFINFO, []byte{2})
+	b.AddSymbol(`"".b`, SRODATA, []byte{3})
+	file := b.Build()
+
+	if symbol, ok := file.Lookup(`"".b`); !ok || symbol != &file.Symbols[2] {
+		t.Errorf("the symbol should be found: %v, %v", symbol, ok)
+	}
+	if _, ok := file.Lookup(`"".c`); ok {
+		t.Errorf("the undefined symbol should not be found")
+	}
+
+	if err := file.RenameSymbol(`"".b`, `"".c`); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if _, ok := file.Lookup(`"".b`); ok {
+		t.Errorf("the renamed symbol should not be found by the old name")
+	}
+	if symbol, ok := file.Lookup(`"".c`); !ok || symbol != &file.Symbols[2] {
+		t.Errorf("the renamed symbol should be found by the new name: %v, %v", symbol, ok)
+	}
+
+	stripped, err := file.Strip(SDWARFINFO)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if symbol, ok := stripped.Lookup(`"".c`); !ok || symbol != &stripped.Symbols[1] {
+		t.Errorf("the symbol should be found in the stripped file: %v, %v", symbol, ok)
+	}
+	if _, ok := stripped.Lookup(`go.info."".a`); ok {
+		t.Errorf("the stripped symbol should not be found")
+	}
+}
@@ -0,0 +1,88 @@
+package goobj
+
+import "fmt"
+
+// RenameSymbol renames the symbol defined in the object file. The relocations and the other references
+// to the symbol in the object file refer to the new name too.
+// The error is returned if the symbol is not defined or the new name is already defined.
+func (f *File) RenameSymbol(from, to string) error {
+	if to == "" {
+		return fmt.Errorf("the new name of %s is empty", from)
+	}
+	if f.isDefined(to) {
+		return fmt.Errorf("the symbol %s is already defined", to)
+	}
+
+	renamed := false
+	for _, symbol := range f.Symbols {
+		if symbol.IDIndex <= 0 || symbol.IDIndex >= int64(len(f.SymbolReferences)) {
+			continue
+		}
+		if reference := &f.SymbolReferences[symbol.IDIndex]; reference.Name == from {
+			reference.Name = to
+			renamed = true
+		}
+	}
+	if !renamed {
+		return fmt.Errorf("the symbol %s is not defined", from)
+	}
+	f.symbolsByName = nil
+	return nil
+}
+
+// RetargetRelocations changes the target of the relocations which refer to `from` to `to`, and returns
+// the number of the changed relocations. The relocations of the symbol `to` itself are not changed so that
+// `to` can call `from`. `from` may be defined in the object file, for example, to interpose the shim on the function
+// of the same package.
+func (f *File) RetargetRelocations(from, to string) (int, error) {
+	if to == "" {
+		return 0, fmt.Errorf("the new target of %s is empty", from)
+	}
+
+	// the new references, which are keyed by the version of the old ones.
+	newIndices := make(map[int64]int64)
+	numRetargeted := 0
+	for i := range f.Symbols {
+		symbol := &f.Symbols[i]
+		if f.referenceName(symbol.IDIndex) == to {
+			continue
+		}
+
+		for j := range symbol.Relocations {
+			reloc := &symbol.Relocations[j]
+			if f.referenceName(reloc.IDIndex) != from {
+				continue
+			}
+
+			version := f.SymbolReferences[reloc.IDIndex].Version
+			index, ok := newIndices[version]
+			if !ok {
+				index = f.findOrAddReference(SymbolReference{Name: to, Version: version})
+				newIndices[version] = index
+			}
+			reloc.IDIndex = index
+			numRetargeted++
+		}
+	}
+	return numRetargeted, nil
+}
+
+// isDefined returns true if the symbol of the given name is defined in the object file.
+func (f *File) isDefined(name string) bool {
+	for _, symbol := range f.Symbols {
+		if f.referenceName(symbol.IDIndex) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *File) findOrAddReference(reference SymbolReference) int64 {
+	for i, r := range f.SymbolReferences {
+		if i != 0 && r == reference {
+			return int64(i)
+		}
+	}
+	f.SymbolReferences = append(f.SymbolReferences, reference)
+	return int64(len(f.SymbolReferences) - 1)
+}
@@ -0,0 +1,92 @@
+package goobj
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFile_RenameSymbol(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	if err := file.RenameSymbol(`"".main`, `"".realMain`); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	buff := &bytes.Buffer{}
+	if _, err := file.WriteTo(buff); err != nil {
+		t.Fatalf("failed to write the file: %v", err)
+	}
+	reparsed, err := ParseBytes(buff.Bytes())
+	if err != nil {
+		t.Fatalf("failed to parse the written file: %v", err)
+	}
+
+	if name := reparsed.SymbolReferences[reparsed.Symbols[0].IDIndex].Name; name != `"".realMain` {
+		t.Errorf("the symbol should be renamed, but %s", name)
+	}
+}
+
+func TestFile_RenameSymbol_InvalidName(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	for i, testData := range []struct{ from, to string }{
+		{from: `"".main`, to: `"".init`},
+		{from: `"".main`, to: ""},
+		{from: `fmt.Println`, to: `"".println`},
+	} {
+		if err := file.RenameSymbol(testData.from, testData.to); err == nil {
+			t.Errorf("[%d] error should not be nil", i)
+		}
+	}
+}
+
+func TestFile_RetargetRelocations(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	main := b.AddSymbol(`"".main`, STEXT, make([]byte, 10))
+	b.AddRelocation(main, Relocation{Offset: 1, Size: 4, Type: R_CALL}, "pkg.Foo")
+	b.AddRelocation(main, Relocation{Offset: 6, Size: 4, Type: R_CALL}, "pkg.Foo")
+	shim := b.AddSymbol("pkg.FooShim", STEXT, make([]byte, 5))
+	b.AddRelocation(shim, Relocation{Offset: 1, Size: 4, Type: R_CALL}, "pkg.Foo")
+	file := b.Build()
+
+	num, err := file.RetargetRelocations("pkg.Foo", "pkg.FooShim")
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if num != 2 {
+		t.Errorf("the number of retargeted relocations should be 2, but %d", num)
+	}
+	for i, reloc := range file.Symbols[0].Relocations {
+		if name := file.SymbolReferences[reloc.IDIndex].Name; name != "pkg.FooShim" {
+			t.Errorf("[%d] the target should be pkg.FooShim, but %s", i, name)
+		}
+	}
+	if name := file.SymbolReferences[file.Symbols[1].Relocations[0].IDIndex].Name; name != "pkg.Foo" {
+		t.Errorf("the relocation of the shim should not be changed, but %s", name)
+	}
+}
+
+func TestFile_RetargetRelocations_DefinedSymbol(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	main := b.AddSymbol(`"".main`, STEXT, make([]byte, 5))
+	b.AddRelocation(main, Relocation{Offset: 1, Size: 4, Type: R_CALL}, `"".foo`)
+	b.AddSymbol(`"".foo`, STEXT, make([]byte, 1))
+	shim := b.AddSymbol(`"".fooShim`, STEXT, make([]byte, 5))
+	b.AddRelocation(shim, Relocation{Offset: 1, Size: 4, Type: R_CALL}, `"".foo`)
+	file := b.Build()
+
+	num, err := file.RetargetRelocations(`"".foo`, `"".fooShim`)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if num != 1 {
+		t.Errorf("the number of retargeted relocations should be 1, but %d", num)
+	}
+	if name := file.SymbolReferences[file.Symbols[0].Relocations[0].IDIndex].Name; name != `"".fooShim` {
+		t.Errorf("the target should be \"\".fooShim, but %s", name)
+	}
+	if name := file.SymbolReferences[file.Symbols[2].Relocations[0].IDIndex].Name; name != `"".foo` {
+		t.Errorf("the relocation of the shim should not be changed, but %s", name)
+	}
+	if _, ok := file.Lookup(`"".foo`); !ok {
+		t.Errorf("the original function should be still defined")
+	}
+}
@@ -0,0 +1,194 @@
+package goobj
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Scanner reads the symbols of the object file one by one, so that the large object file can be inspected
+// without loading the whole file into the memory.
+//
+// The go19ld, go17ld and indexed formats are supported. The indexed format requires the reader which implements
+// io.ReaderAt, and the blocks before the data block are read into the memory. The symbols of the go13ld format
+// can not be read without the data. Use Parse for it.
+// The offsets given to ReadAt are relative to the first byte of the object file, so io.ReaderAt must start there.
+// To scan the member of the package archive, give io.SectionReader of the member. See ArchiveMember.NewScanner.
+type Scanner struct {
+	parser *parser
+	// the reader of the indexed format. Nil if the file is in the other format.
+	indexed *indexedReader
+	// the raw reader, which is used to read the data if it implements io.ReaderAt.
+	raw     io.Reader
+	index   int
+	lengths Header
+	err     error
+}
+
+// NewScanner reads the header, the imports and the symbol references of the object file, and returns the scanner
+// to read the symbols. The data block is skipped.
+func NewScanner(r io.Reader) (*Scanner, error) {
+	p := newParser(bufio.NewReader(r))
+	if err := p.skipHeader(); err != nil {
+		return nil, p.parseError(err)
+	}
+	switch p.Format {
+	case FormatGo19, FormatGo17:
+	case FormatIndexed:
+		return newIndexedScanner(p, r)
+	default:
+		return nil, fmt.Errorf("the %s format is not supported by the scanner. Use Parse instead", p.Format)
+	}
+
+	err := p.parseSections([]section{
+		{SectionHeader, p.checkVersion},
+		{SectionDependencies, p.parseDependencies},
+		{SectionReferences, p.parseReferences},
+		{SectionData, p.skipData},
+	})
+	if err != nil {
+		return nil, p.parseError(err)
+	}
+
+	p.section = SectionSymbols
+	return &Scanner{parser: p, raw: r}, nil
+}
+
+// newIndexedScanner returns the scanner of the indexed format. The magic header is already read.
+func newIndexedScanner(p *parser, raw io.Reader) (*Scanner, error) {
+	readerAt, ok := raw.(io.ReaderAt)
+	if !ok {
+		return nil, errors.New("the reader does not implement io.ReaderAt, which is required to scan the indexed format")
+	}
+
+	version, _ := indexedMagicVersion(p.magic)
+	r := &indexedReader{
+		raw:            readerAt,
+		version:        version,
+		magicPosition:  p.reader.numReadBytes - int64(len(p.magic)),
+		maxRelocations: p.reader.limit().MaxRelocations,
+	}
+	if err := r.load(p.reader.limit().MaxObjectSize); err != nil {
+		return nil, r.parseError(SectionHeader, 0, -1, err)
+	}
+	if err := r.readImports(&p.File); err != nil {
+		return nil, r.parseError(SectionDependencies, r.offsets[blkAutolib], -1, err)
+	}
+	if err := r.readReferences(&p.File); err != nil {
+		return nil, r.parseError(SectionReferences, r.offsets[blkPkgIdx], -1, err)
+	}
+
+	p.DataBlockPosition = r.magicPosition + int64(r.offsets[blkData])
+	p.Header.DataLength = r.dataSize()
+	p.section = SectionSymbols
+	return &Scanner{parser: p, indexed: r, raw: raw}, nil
+}
+
+// skipData parses the lengths and skips the data block.
+func (p *parser) skipData() error {
+	if err := p.parseLengths(); err != nil {
+		return err
+	}
+
+	p.DataBlockPosition = p.reader.numReadBytes
+	p.reader.skip(p.Header.DataLength)
+	return p.reader.err
+}
+
+// File returns the object file read so far. It has the header, the imports and the symbol references,
+// but has neither the data block nor the symbols.
+func (s *Scanner) File() *File {
+	return &s.parser.File
+}
+
+// Next reads the next symbol. io.EOF is returned after the last symbol is read and the footer is checked.
+// The symbol's DataAddr is the offset from DataBlockPosition. Use ReadData to read the data.
+func (s *Scanner) Next() (*Symbol, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	symbol, err := s.next()
+	if err != nil {
+		parseErr := s.parser.parseError(err).(*ParseError)
+		if parseErr.Section == SectionSymbols {
+			parseErr.SymbolIndex = s.index
+		}
+		s.err = parseErr
+		return nil, s.err
+	}
+	if symbol == nil {
+		s.err = io.EOF
+		return nil, s.err
+	}
+
+	s.index++
+	return symbol, nil
+}
+
+// next reads the next symbol. The nil symbol is returned when the symbols end.
+func (s *Scanner) next() (*Symbol, error) {
+	if s.indexed != nil {
+		return s.nextIndexed()
+	}
+
+	p := s.parser
+	p.symbolName = ""
+	b := p.reader.readByte()
+	if p.reader.err != nil {
+		return nil, p.reader.err
+	}
+
+	if b == 0xff {
+		p.section = SectionFooter
+		if err := p.checkLengths(s.lengths); err != nil {
+			return nil, err
+		}
+		if err := p.skipFooter(); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	} else if b != 0xfe {
+		return nil, fmt.Errorf("sanity check failed: %#x", b)
+	}
+
+	symbol, err := p.readSymbol()
+	if err != nil {
+		return nil, err
+	}
+	s.lengths.addLengths(&symbol)
+	return &symbol, nil
+}
+
+// nextIndexed reads the next symbol of the indexed format.
+func (s *Scanner) nextIndexed() (*Symbol, error) {
+	if s.index >= s.indexed.numDefs {
+		return nil, nil
+	}
+
+	symbol, err := s.indexed.readSymbol(&s.parser.File, s.index)
+	if err != nil {
+		return nil, s.indexed.symbolError(&s.parser.File, s.index, err)
+	}
+	return &symbol, nil
+}
+
+// ReadData reads the data at the given address, such as the symbol's content and the pcvalue table.
+// The error is returned if the reader given to NewScanner does not implement io.ReaderAt.
+// The reader must start at the first byte of the object file. Otherwise the wrong bytes are read.
+func (s *Scanner) ReadData(addr DataAddr) ([]byte, error) {
+	r, ok := s.raw.(io.ReaderAt)
+	if !ok {
+		return nil, errors.New("the reader does not implement io.ReaderAt")
+	}
+	if addr.Offset < 0 || addr.Size < 0 || addr.Offset+addr.Size > s.parser.Header.DataLength {
+		return nil, fmt.Errorf("data out of the data block: %+v", addr)
+	}
+
+	buff := make([]byte, addr.Size)
+	if _, err := r.ReadAt(buff, s.parser.DataBlockPosition+addr.Offset); err != nil {
+		return nil, err
+	}
+	return buff, nil
+}
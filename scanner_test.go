@@ -0,0 +1,184 @@
+package goobj
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	expected := parseFileForTesting(t, helloworldObjPath)
+
+	scanner, err := NewScanner(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file := scanner.File()
+	if !reflect.DeepEqual(expected.SymbolReferences, file.SymbolReferences) || file.DataBlock != nil {
+		t.Errorf("invalid file: %+v", file)
+	}
+	if file.DataBlockPosition != expected.DataBlockPosition {
+		t.Errorf("the data block position should be %d, but %d", expected.DataBlockPosition, file.DataBlockPosition)
+	}
+
+	var symbols []Symbol
+	for {
+		symbol, err := scanner.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("error should be nil, but %v", err)
+		}
+		symbols = append(symbols, *symbol)
+	}
+	if !reflect.DeepEqual(expected.Symbols, symbols) {
+		t.Errorf("the symbols should be same as the parsed ones")
+	}
+	if _, err := scanner.Next(); err != io.EOF {
+		t.Errorf("error should be io.EOF, but %v", err)
+	}
+
+	content, err := scanner.ReadData(symbols[2].DataAddr)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if string(content) != "Hello, playground" {
+		t.Errorf("the data should be Hello, playground, but %q", content)
+	}
+}
+
+func TestScanner_Truncated(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	scanner, err := NewScanner(bytes.NewReader(data[:len(data)-100]))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	for {
+		_, err := scanner.Next()
+		if err == nil {
+			continue
+		}
+
+		parseErr, ok := err.(*ParseError)
+		if !ok || parseErr.Err != io.ErrUnexpectedEOF || parseErr.SymbolIndex <= 0 {
+			t.Errorf("the error should be the unexpected EOF: %#v", err)
+		}
+		break
+	}
+}
+
+func TestScanner_ReadData_NotReaderAt(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	scanner, err := NewScanner(io.MultiReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if _, err := scanner.ReadData(DataAddr{Size: 1}); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestNewScanner_UnsupportedFormat(t *testing.T) {
+	in := "go object linux amd64 go1.6 X:none\n!\n\x00\x00go13ld\x01"
+	if _, err := NewScanner(strings.NewReader(in)); err == nil || !strings.Contains(err.Error(), "go13ld") {
+		t.Errorf("the error should be the unsupported format, but %v", err)
+	}
+}
+
+// readerAtForTesting records the regions read by ReadAt.
+type readerAtForTesting struct {
+	*bytes.Reader
+	regions []DataAddr
+}
+
+func (r *readerAtForTesting) ReadAt(p []byte, off int64) (int, error) {
+	r.regions = append(r.regions, DataAddr{Offset: off, Size: int64(len(p))})
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestScanner_Indexed(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldIndexedObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	expected := parseFileForTesting(t, helloworldIndexedObjPath)
+
+	r := &readerAtForTesting{Reader: bytes.NewReader(data)}
+	scanner, err := NewScanner(r)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	file := scanner.File()
+	if !reflect.DeepEqual(expected.Imports, file.Imports) || file.DataBlock != nil {
+		t.Errorf("invalid file: %+v", file)
+	}
+	if file.DataBlockPosition != expected.DataBlockPosition || file.Header.DataLength != expected.Header.DataLength {
+		t.Errorf("the data block should be at %d (%d bytes), but %d (%d bytes)", expected.DataBlockPosition,
+			expected.Header.DataLength, file.DataBlockPosition, file.Header.DataLength)
+	}
+	for _, region := range r.regions {
+		if region.Offset < file.DataBlockPosition+file.Header.DataLength && file.DataBlockPosition < region.Offset+region.Size {
+			t.Errorf("the data block should not be read: %+v", region)
+		}
+	}
+
+	var symbols []Symbol
+	for {
+		symbol, err := scanner.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("error should be nil, but %v", err)
+		}
+		symbols = append(symbols, *symbol)
+	}
+	if !reflect.DeepEqual(expected.Symbols, symbols) {
+		t.Errorf("the symbols should be same as the parsed ones")
+	}
+	if !reflect.DeepEqual(expected.SymbolReferences, file.SymbolReferences) {
+		t.Errorf("the symbol references should be same as the parsed ones")
+	}
+
+	for i, symbol := range symbols {
+		content, err := scanner.ReadData(symbol.DataAddr)
+		if err != nil {
+			t.Fatalf("[%d] error should be nil, but %v", i, err)
+		}
+		if !bytes.Equal(dataOf(expected, symbol.DataAddr), content) {
+			t.Errorf("[%d] the data should be same as the parsed one", i)
+		}
+	}
+}
+
+func TestScanner_Indexed_NotReaderAt(t *testing.T) {
+	data, err := ioutil.ReadFile(helloworldIndexedObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	if _, err := NewScanner(io.MultiReader(bytes.NewReader(data))); err == nil || !strings.Contains(err.Error(), "io.ReaderAt") {
+		t.Errorf("the error should be the reader not implementing io.ReaderAt, but %v", err)
+	}
+}
+
+func TestScanner_Indexed_Limit(t *testing.T) {
+	in := "\x00go120ld" + strings.Repeat("\x00", 100)
+	if _, err := NewScanner(strings.NewReader(in)); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
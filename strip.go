@@ -0,0 +1,167 @@
+package goobj
+
+import "fmt"
+
+// Strip returns the copy of the object file without the symbols of the given kinds.
+//
+// The data block is compacted to hold only the data of the remaining symbols, and the symbol references
+// no remaining symbol refers to are removed. The indices of the references and the data addresses are updated accordingly.
+// The error is returned if the file is not in the go19ld format, the remaining symbol refers to the removed symbol
+// (by the relocation, the go type, the local variable, the funcdata and so on), or the data address of the remaining
+// symbol is out of the data block.
+func (f *File) Strip(kinds ...SymKind) (*File, error) {
+	if f.Format != FormatGo19 {
+		return nil, fmt.Errorf("can not strip the object file of the %s format. Only %s is supported", f.Format, FormatGo19)
+	}
+
+	removed := make(map[SymKind]bool)
+	for _, kind := range kinds {
+		removed[kind] = true
+	}
+
+	// the cache of SymbolsByName is not copied since the indices of the symbols change.
+	stripped := &File{
+		Imports:           f.Imports,
+		DataBlockPosition: -1,
+		Header:            f.Header,
+		RawHeader:         f.RawHeader,
+		Format:            FormatGo19,
+	}
+	removedIndices := make(map[int64]bool)
+	for _, symbol := range f.Symbols {
+		if removed[symbol.Kind] {
+			removedIndices[symbol.IDIndex] = true
+		} else {
+			stripped.Symbols = append(stripped.Symbols, copySymbol(symbol))
+		}
+	}
+	// the symbol of the same name may remain, like the DupOK symbols of the different kinds.
+	for _, symbol := range stripped.Symbols {
+		delete(removedIndices, symbol.IDIndex)
+	}
+	for i := range stripped.Symbols {
+		var err error
+		// the file of the single symbol, to visit the references of the symbol.
+		single := &File{Symbols: stripped.Symbols[i : i+1]}
+		single.forEachReferenceIndex(func(index *int64) {
+			if err == nil && removedIndices[*index] {
+				err = fmt.Errorf("can not strip the symbol %s: the symbol %s refers to it",
+					f.referenceName(*index), f.referenceName(stripped.Symbols[i].IDIndex))
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stripped.removeUnusedReferences(f.SymbolReferences)
+	if err := stripped.compactData(f.DataBlock); err != nil {
+		return nil, err
+	}
+	stripped.setLengths()
+	return stripped, nil
+}
+
+func copySymbol(symbol Symbol) Symbol {
+	symbol.Relocations = append([]Relocation(nil), symbol.Relocations...)
+	if symbol.Func != nil {
+		fields := *symbol.Func
+		fields.Local = append([]Local(nil), fields.Local...)
+		fields.PCData = append([]DataAddr(nil), fields.PCData...)
+		fields.FuncDataIndex = append([]int64(nil), fields.FuncDataIndex...)
+		fields.FuncDataOffset = append([]int64(nil), fields.FuncDataOffset...)
+		fields.FileIndex = append([]int64(nil), fields.FileIndex...)
+		fields.InlineTree = append([]InlinedCall(nil), fields.InlineTree...)
+		symbol.Func = &fields
+	}
+	return symbol
+}
+
+// removeUnusedReferences sets the references the symbols refer to, and updates the indices of the symbols.
+func (f *File) removeUnusedReferences(references []SymbolReference) {
+	// the 1st reference is the nil symbol and always kept.
+	newIndices := map[int64]int64{0: 0}
+	f.SymbolReferences = []SymbolReference{{}}
+	f.forEachReferenceIndex(func(index *int64) {
+		if *index < 0 || *index >= int64(len(references)) {
+			return
+		}
+
+		newIndex, ok := newIndices[*index]
+		if !ok {
+			f.SymbolReferences = append(f.SymbolReferences, references[*index])
+			newIndex = int64(len(f.SymbolReferences) - 1)
+			newIndices[*index] = newIndex
+		}
+		*index = newIndex
+	})
+}
+
+// forEachReferenceIndex calls the given function with the pointer to each index of the symbol reference in the symbols.
+func (f *File) forEachReferenceIndex(fn func(index *int64)) {
+	for i := range f.Symbols {
+		symbol := &f.Symbols[i]
+		fn(&symbol.IDIndex)
+		fn(&symbol.GoTypeIndex)
+		for j := range symbol.Relocations {
+			fn(&symbol.Relocations[j].IDIndex)
+		}
+
+		if symbol.Func == nil {
+			continue
+		}
+		for j := range symbol.Func.Local {
+			fn(&symbol.Func.Local[j].AsymIndex)
+			fn(&symbol.Func.Local[j].GotypeIndex)
+		}
+		for j := range symbol.Func.FuncDataIndex {
+			fn(&symbol.Func.FuncDataIndex[j])
+		}
+		for j := range symbol.Func.FileIndex {
+			fn(&symbol.Func.FileIndex[j])
+		}
+		for j := range symbol.Func.InlineTree {
+			fn(&symbol.Func.InlineTree[j].FileIndex)
+			fn(&symbol.Func.InlineTree[j].FuncIndex)
+		}
+	}
+}
+
+// compactData sets the data block which holds only the data the symbols refer to, and updates the data addresses.
+func (f *File) compactData(dataBlock []byte) error {
+	var err error
+	f.DataBlock = nil
+	f.forEachDataAddr(func(addr *DataAddr) {
+		if addr.Offset < 0 || addr.Size < 0 || addr.Offset+addr.Size > int64(len(dataBlock)) {
+			if err == nil {
+				err = fmt.Errorf("data out of the data block: %+v", *addr)
+			}
+			return
+		}
+
+		offset := int64(len(f.DataBlock))
+		f.DataBlock = append(f.DataBlock, dataBlock[addr.Offset:addr.Offset+addr.Size]...)
+		addr.Offset = offset
+	})
+	return err
+}
+
+// forEachDataAddr calls the given function with the pointer to each data address in the symbols,
+// in the order the data are laid out in the go19ld format.
+func (f *File) forEachDataAddr(fn func(addr *DataAddr)) {
+	for i := range f.Symbols {
+		symbol := &f.Symbols[i]
+		fn(&symbol.DataAddr)
+
+		if symbol.Func == nil {
+			continue
+		}
+		fn(&symbol.Func.PCSP)
+		fn(&symbol.Func.PCFile)
+		fn(&symbol.Func.PCLine)
+		fn(&symbol.Func.PCInline)
+		for j := range symbol.Func.PCData {
+			fn(&symbol.Func.PCData[j])
+		}
+	}
+}
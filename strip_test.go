@@ -0,0 +1,127 @@
+package goobj
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFile_Strip(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	stripped, err := file.Strip(SDWARFINFO, SDWARFRANGE, SDWARFLOC)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+
+	buff := &bytes.Buffer{}
+	if _, err := stripped.WriteTo(buff); err != nil {
+		t.Fatalf("failed to write the file: %v", err)
+	}
+	reparsed, err := ParseBytes(buff.Bytes())
+	if err != nil {
+		t.Fatalf("failed to parse the stripped file: %v", err)
+	}
+
+	if len(reparsed.Symbols) != len(file.Symbols)-4 {
+		t.Errorf("the number of symbols should be %d, but %d", len(file.Symbols)-4, len(reparsed.Symbols))
+	}
+	for _, symbol := range reparsed.Symbols {
+		if symbol.Kind.IsDWARF() {
+			t.Errorf("the DWARF symbol should be removed: %s", reparsed.SymbolReferences[symbol.IDIndex].Name)
+		}
+	}
+	for _, reference := range reparsed.SymbolReferences {
+		if reference.Name == `go.info."".main` {
+			t.Errorf("the unused reference should be removed: %s", reference.Name)
+		}
+	}
+	if reparsed.Header.DataLength >= file.Header.DataLength {
+		t.Errorf("the data block should be smaller: %d >= %d", reparsed.Header.DataLength, file.Header.DataLength)
+	}
+
+	original, strippedMain := file.Symbols[0], reparsed.Symbols[0]
+	if file.SymbolReferences[original.IDIndex] != reparsed.SymbolReferences[strippedMain.IDIndex] {
+		t.Errorf("the 1st symbol should be same")
+	}
+	if !bytes.Equal(dataOf(file, original.DataAddr), dataOf(reparsed, strippedMain.DataAddr)) {
+		t.Errorf("the data should be same")
+	}
+	for i, reloc := range original.Relocations {
+		if file.SymbolReferences[reloc.IDIndex] != reparsed.SymbolReferences[strippedMain.Relocations[i].IDIndex] {
+			t.Errorf("[%d] the relocation target should be same", i)
+		}
+	}
+	if !bytes.Equal(dataOf(file, original.Func.PCLine), dataOf(reparsed, strippedMain.Func.PCLine)) {
+		t.Errorf("the pcline table should be same")
+	}
+}
+
+func TestFile_Strip_ReferencedSymbol(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	text := b.AddSymbol(`"".main`, STEXT, []byte{0xc3})
+	b.AddRelocation(text, Relocation{Size: 4, Type: R_ADDROFF}, `go.info."".main`)
+	b.AddSymbol(`go.info."".main`, SDWARFINFO, []byte{1, 2, 3})
+	b.AddSymbol(`go.loc."".main`, SDWARFLOC, []byte{4, 5})
+	file := b.Build()
+
+	if _, err := file.Strip(SDWARFINFO); err == nil {
+		t.Errorf("error should not be nil when the remaining symbol refers to the stripped symbol")
+	}
+
+	stripped, err := file.Strip(SDWARFLOC)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if len(stripped.Symbols) != 2 || len(stripped.SymbolReferences) != 3 {
+		t.Fatalf("invalid file: %+v", stripped)
+	}
+	if !bytes.Equal(stripped.DataBlock, []byte{0xc3, 1, 2, 3}) {
+		t.Errorf("the data block should be compacted: %v", stripped.DataBlock)
+	}
+}
+
+func TestFile_Strip_ReferencedByFunc(t *testing.T) {
+	for i, kind := range []SymKind{SRODATA, SDATA, SBSS} {
+		b := NewObjectBuilder("linux", "amd64", "go1.10")
+		text := b.AddSymbol(`"".main`, STEXT, []byte{0xc3})
+		b.AddSymbol("type.int", SRODATA, []byte{1})
+		b.AddSymbol("gclocals·a", SDATA, []byte{2})
+		b.AddSymbol(`"".x`, SBSS, nil)
+		if err := b.AddLocal(text, `"".x`, -8, 1, "type.int"); err != nil {
+			t.Fatalf("error should be nil, but %v", err)
+		}
+		if err := b.AddFuncData(text, "gclocals·a", 0); err != nil {
+			t.Fatalf("error should be nil, but %v", err)
+		}
+		file := b.Build()
+
+		if _, err := file.Strip(kind); err == nil {
+			t.Errorf("[%d] error should not be nil when the func refers to the stripped symbol", i)
+		}
+	}
+
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".data`, SNOPTRDATA, []byte{1}).GoTypeIndex = b.Reference("type.int", 0)
+	b.AddSymbol("type.int", SRODATA, []byte{2})
+	if _, err := b.Build().Strip(SRODATA); err == nil {
+		t.Errorf("error should not be nil when the go type is stripped")
+	}
+}
+
+func TestFile_Strip_UnsupportedFormat(t *testing.T) {
+	file := parseFileForTesting(t, helloworldIndexedObjPath)
+	if _, err := file.Strip(SDWARFINFO); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestFile_Strip_DataOutOfRange(t *testing.T) {
+	file := &File{SymbolReferences: []SymbolReference{{}}, Symbols: []Symbol{{DataAddr: DataAddr{Offset: 1, Size: 1}}}}
+	file.Format = FormatGo19
+	if _, err := file.Strip(SDWARFINFO); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func dataOf(file *File, addr DataAddr) []byte {
+	return file.DataBlock[addr.Offset : addr.Offset+addr.Size]
+}
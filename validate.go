@@ -0,0 +1,186 @@
+package goobj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is the error returned by Validate. It holds all the problems found in the object file.
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		messages = append(messages, problem.Error())
+	}
+	return fmt.Sprintf("%d problem(s) found: %s", len(e.Problems), strings.Join(messages, "; "))
+}
+
+// Validate checks the invariants the parser and the linker assume:
+//
+//   - every index of the symbol reference is in range
+//   - every relocation is in the data of its symbol
+//   - every data, including the pcvalue tables, is in the data block, and the total size of the data equals
+//     the size of the data block (except the indexed format, whose symbols share the data)
+//   - every pcvalue table is decodable and does not exceed the size of its function
+//   - no two non-DupOK symbols have the same name
+//
+// Returns *ValidationError if any problem is found.
+func (f *File) Validate() error {
+	v := &validator{file: f}
+	v.validateReferences()
+	v.validateData()
+	v.validatePCValueTables()
+	v.validateDuplicates()
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+type validator struct {
+	file     *File
+	problems []error
+}
+
+func (v *validator) addProblem(symbolIndex int, format string, args ...interface{}) {
+	prefix := fmt.Sprintf("symbol %d", symbolIndex)
+	if name := v.file.referenceName(v.file.Symbols[symbolIndex].IDIndex); name != "" {
+		prefix += " " + name
+	}
+	v.problems = append(v.problems, fmt.Errorf("%s: %s", prefix, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) validateReferences() {
+	numReferences := int64(len(v.file.SymbolReferences))
+	for i := range v.file.Symbols {
+		symbol := &v.file.Symbols[i]
+		checkIndex := func(name string, index int64) {
+			if index < 0 || index >= numReferences {
+				v.addProblem(i, "%s out of range: %d", name, index)
+			}
+		}
+
+		checkIndex("IDIndex", symbol.IDIndex)
+		checkIndex("GoTypeIndex", symbol.GoTypeIndex)
+		for j, reloc := range symbol.Relocations {
+			checkIndex(fmt.Sprintf("IDIndex of the relocation %d", j), reloc.IDIndex)
+		}
+
+		if symbol.Func == nil {
+			continue
+		}
+		for j, local := range symbol.Func.Local {
+			checkIndex(fmt.Sprintf("AsymIndex of the local %d", j), local.AsymIndex)
+			checkIndex(fmt.Sprintf("GotypeIndex of the local %d", j), local.GotypeIndex)
+		}
+		for j, index := range symbol.Func.FuncDataIndex {
+			checkIndex(fmt.Sprintf("FuncDataIndex %d", j), index)
+		}
+		for j, index := range symbol.Func.FileIndex {
+			checkIndex(fmt.Sprintf("FileIndex %d", j), index)
+		}
+		for j, call := range symbol.Func.InlineTree {
+			checkIndex(fmt.Sprintf("FileIndex of the inlined call %d", j), call.FileIndex)
+			checkIndex(fmt.Sprintf("FuncIndex of the inlined call %d", j), call.FuncIndex)
+		}
+	}
+}
+
+func (v *validator) validateData() {
+	var totalSize int64
+	for i := range v.file.Symbols {
+		symbol := &v.file.Symbols[i]
+		for _, addr := range v.dataAddrs(symbol) {
+			if addr.Offset < 0 || addr.Size < 0 || addr.Offset+addr.Size > int64(len(v.file.DataBlock)) {
+				v.addProblem(i, "data out of the data block: %+v", addr)
+			}
+			totalSize += addr.Size
+		}
+
+		for j, reloc := range symbol.Relocations {
+			if reloc.Offset < 0 || reloc.Size < 0 || reloc.Offset+reloc.Size > symbol.DataAddr.Size {
+				v.addProblem(i, "relocation %d out of the data: offset %#x, size %d, data size %d", j, reloc.Offset, reloc.Size, symbol.DataAddr.Size)
+			}
+		}
+	}
+
+	// the data of the other formats are not laid out back to back in the data block.
+	switch v.file.Format {
+	case FormatGo13, FormatGo17, FormatGo19:
+	default:
+		return
+	}
+	if totalSize != int64(len(v.file.DataBlock)) {
+		v.problems = append(v.problems, fmt.Errorf("the total size of the data %d does not equal the size of the data block %d", totalSize, len(v.file.DataBlock)))
+	}
+}
+
+// dataAddrs returns the data addresses of the symbol, in the order the data are laid out.
+func (v *validator) dataAddrs(symbol *Symbol) []DataAddr {
+	addrs := []DataAddr{symbol.DataAddr}
+	if symbol.Func != nil {
+		addrs = append(addrs, symbol.Func.PCSP, symbol.Func.PCFile, symbol.Func.PCLine, symbol.Func.PCInline)
+		addrs = append(addrs, symbol.Func.PCData...)
+	}
+	return addrs
+}
+
+func (v *validator) validatePCValueTables() {
+	for i := range v.file.Symbols {
+		symbol := &v.file.Symbols[i]
+		if symbol.Func == nil {
+			continue
+		}
+
+		tables := []struct {
+			name string
+			addr DataAddr
+		}{
+			{"pcsp", symbol.Func.PCSP},
+			{"pcfile", symbol.Func.PCFile},
+			{"pcline", symbol.Func.PCLine},
+			{"pcinline", symbol.Func.PCInline},
+		}
+		for j, addr := range symbol.Func.PCData {
+			tables = append(tables, struct {
+				name string
+				addr DataAddr
+			}{fmt.Sprintf("pcdata %d", j), addr})
+		}
+
+		for _, table := range tables {
+			ranges, err := v.file.decodePCValue(table.addr)
+			if err != nil {
+				v.addProblem(i, "invalid %s table: %v", table.name, err)
+				continue
+			}
+			if len(ranges) > 0 && ranges[len(ranges)-1].EndPC > symbol.Size {
+				v.addProblem(i, "%s table exceeds the function: %#x > %#x", table.name, ranges[len(ranges)-1].EndPC, symbol.Size)
+			}
+		}
+	}
+}
+
+func (v *validator) validateDuplicates() {
+	defined := make(map[SymbolReference]int)
+	for i, symbol := range v.file.Symbols {
+		if symbol.DupOK || symbol.IDIndex <= 0 || symbol.IDIndex >= int64(len(v.file.SymbolReferences)) {
+			continue
+		}
+
+		// the anonymous symbols like the pcvalue tables of the indexed format have no name.
+		reference := v.file.SymbolReferences[symbol.IDIndex]
+		if reference.Name == "" {
+			continue
+		}
+		if j, ok := defined[reference]; ok {
+			v.addProblem(i, "the name is already defined by the symbol %d", j)
+			continue
+		}
+		defined[reference] = i
+	}
+}
@@ -0,0 +1,90 @@
+package goobj
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFile_Validate(t *testing.T) {
+	for _, path := range []string{helloworldObjPath, helloworldIndexedObjPath} {
+		file := parseFileForTesting(t, path)
+		if err := file.Validate(); err != nil {
+			t.Errorf("%s should be valid, but %v", path, err)
+		}
+	}
+}
+
+func TestFile_Validate_Problems(t *testing.T) {
+	for i, testData := range []struct {
+		modify   func(b *ObjectBuilder, symbol *Symbol)
+		expected string
+	}{
+		{
+			modify:   func(b *ObjectBuilder, symbol *Symbol) { symbol.GoTypeIndex = 100 },
+			expected: "GoTypeIndex out of range: 100",
+		},
+		{
+			modify: func(b *ObjectBuilder, symbol *Symbol) {
+				b.AddRelocation(symbol, Relocation{Offset: 1, Size: 4, Type: R_CALL}, "fmt.Println")
+			},
+			expected: "relocation 0 out of the data",
+		},
+		{
+			modify:   func(b *ObjectBuilder, symbol *Symbol) { symbol.DataAddr.Size = 10 },
+			expected: "data out of the data block",
+		},
+		{
+			// the data not referred by any symbol
+			modify:   func(b *ObjectBuilder, symbol *Symbol) { b.addData([]byte{0}) },
+			expected: "the total size of the data 4 does not equal the size of the data block 5",
+		},
+		{
+			modify:   func(b *ObjectBuilder, symbol *Symbol) { _ = b.SetPCLN(symbol, nil, nil, nil, nil, []byte{0x02}) },
+			expected: "invalid pcdata 0 table",
+		},
+		{
+			modify: func(b *ObjectBuilder, symbol *Symbol) {
+				table, _ := EncodePCValue([]PCValueRange{{StartPC: 0, EndPC: 8, Value: 1}}, 1)
+				_ = b.SetPCLN(symbol, table, nil, nil, nil)
+			},
+			expected: "pcsp table exceeds the function: 0x8 > 0x4",
+		},
+		{
+			modify:   func(b *ObjectBuilder, symbol *Symbol) { b.AddSymbol(`"".main`, STEXT, nil) },
+			expected: `symbol 1 "".main: the name is already defined by the symbol 0`,
+		},
+	} {
+		b := NewObjectBuilder("linux", "amd64", "go1.10")
+		symbol := b.AddSymbol(`"".main`, STEXT, []byte{0x90, 0x90, 0x90, 0xc3})
+		testData.modify(b, symbol)
+
+		err := b.Build().Validate()
+		if err == nil || !strings.Contains(err.Error(), testData.expected) {
+			t.Errorf("[%d] error should contain %q, but %v", i, testData.expected, err)
+		}
+	}
+}
+
+func TestFile_Validate_DupOK(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.10")
+	b.AddSymbol(`"".data`, SRODATA, []byte{1})
+	b.AddSymbol(`"".data`, SRODATA, []byte{1}).DupOK = true
+	if err := b.Build().Validate(); err != nil {
+		t.Errorf("error should be nil, but %v", err)
+	}
+}
+
+func TestFile_Validate_DataSizeOfFormat(t *testing.T) {
+	b := NewObjectBuilder("linux", "amd64", "go1.16")
+	b.AddSymbol(`"".data`, SRODATA, []byte{1})
+	b.addData([]byte{0})
+	file := b.Build()
+	if err := file.Validate(); err == nil {
+		t.Errorf("the total size should be checked for the go19ld format regardless of the go version")
+	}
+
+	file.Format = FormatIndexed
+	if err := file.Validate(); err != nil {
+		t.Errorf("the total size should not be checked for the indexed format, but %v", err)
+	}
+}
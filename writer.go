@@ -0,0 +1,294 @@
+package goobj
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WriteTo writes the object file in the go19ld format. The error is returned if the file is in the other format.
+//
+// The data block is rebuilt from the DataAddr of the symbols in the order they are written,
+// so the regions of the data block not referred by any symbol are dropped.
+// The file is encoded before anything is written to w, so nothing is written if the encoding fails.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	if f.Format != FormatGo19 {
+		return 0, fmt.Errorf("can not write the object file of the %s format. Only %s is supported", f.Format, FormatGo19)
+	}
+
+	encoded := &bytes.Buffer{}
+	writer := newWriter(bufio.NewWriter(encoded), f)
+	if err := writer.writeFile(); err != nil {
+		return 0, err
+	}
+	if err := writer.writer.raw.Flush(); err != nil {
+		return 0, err
+	}
+	return encoded.WriteTo(w)
+}
+
+type writer struct {
+	writer writerWithCounter
+	file   *File
+	// the data to be written, in the order of the DataAddr written.
+	data []DataAddr
+}
+
+func newWriter(raw *bufio.Writer, file *File) *writer {
+	return &writer{writer: writerWithCounter{raw: raw}, file: file}
+}
+
+func (w *writer) writeFile() error {
+	w.writer.write(w.file.RawHeader)
+	w.writer.write(magicHeader)
+	w.writer.writeByte(supportedGoObjVersion)
+
+	for _, dependency := range w.file.Imports {
+		w.writer.writeString(dependency)
+	}
+	w.writer.writeString("")
+
+	// the 1st reference is the nil symbol and not written.
+	for i := 1; i < len(w.file.SymbolReferences); i++ {
+		w.writer.writeByte(0xfe)
+		w.writer.writeString(w.file.SymbolReferences[i].Name)
+		w.writer.writeVarint(w.file.SymbolReferences[i].Version)
+	}
+	w.writer.writeByte(0xff)
+
+	// the symbols are encoded first to know the data to be written.
+	symbols := &bytes.Buffer{}
+	symbolWriter := newWriter(bufio.NewWriter(symbols), w.file)
+	for i := range w.file.Symbols {
+		if err := symbolWriter.writeSymbol(&w.file.Symbols[i]); err != nil {
+			return err
+		}
+	}
+	symbolWriter.writer.writeByte(0xff)
+	if symbolWriter.writer.err != nil {
+		return symbolWriter.writer.err
+	}
+	if err := symbolWriter.writer.raw.Flush(); err != nil {
+		return err
+	}
+
+	if err := w.writeData(symbolWriter.data); err != nil {
+		return err
+	}
+	// the symbols end with 0xff, which is the 1st byte of the footer.
+	w.writer.write(symbols.Bytes())
+	w.writer.write(magicFooter)
+	return w.writer.err
+}
+
+// writeData writes the lengths and the data block.
+func (w *writer) writeData(data []DataAddr) error {
+	lengths := w.file.countLengths()
+	for _, addr := range data {
+		lengths.DataLength += addr.Size
+	}
+	w.writer.writeVarint(lengths.DataLength)
+	w.writer.writeVarint(lengths.NumRelocations)
+	w.writer.writeVarint(lengths.NumPCData)
+	w.writer.writeVarint(lengths.NumLocals)
+	w.writer.writeVarint(lengths.NumFuncData)
+	w.writer.writeVarint(lengths.NumFiles)
+
+	for _, addr := range data {
+		if addr.Offset < 0 || addr.Size < 0 || addr.Offset+addr.Size > int64(len(w.file.DataBlock)) {
+			return fmt.Errorf("data out of the data block: %+v", addr)
+		}
+		w.writer.write(w.file.DataBlock[addr.Offset : addr.Offset+addr.Size])
+	}
+	return w.writer.err
+}
+
+func (w *writer) writeSymbol(symbol *Symbol) error {
+	kind, err := w.file.Header.rawSymKind(symbol.Kind)
+	if err != nil {
+		return err
+	}
+
+	w.writer.writeByte(0xfe)
+	w.writer.writeByte(kind)
+	w.writer.writeVarint(symbol.IDIndex)
+
+	var flags int64
+	if symbol.DupOK {
+		flags |= 0x1
+	}
+	if symbol.Local {
+		flags |= 0x1 << 1
+	}
+	if symbol.Typelink {
+		flags |= 0x1 << 2
+	}
+	w.writer.writeVarint(flags)
+
+	w.writer.writeVarint(symbol.Size)
+	w.writer.writeVarint(symbol.GoTypeIndex)
+	w.writeDataAddr(symbol.DataAddr)
+
+	w.writer.writeVarint(int64(len(symbol.Relocations)))
+	for _, reloc := range symbol.Relocations {
+		relocType, err := w.file.Header.rawRelocType(reloc.Type)
+		if err != nil {
+			return err
+		}
+		w.writer.writeVarint(reloc.Offset)
+		w.writer.writeVarint(reloc.Size)
+		w.writer.writeVarint(relocType)
+		w.writer.writeVarint(reloc.Add)
+		w.writer.writeVarint(reloc.IDIndex)
+	}
+
+	if symbol.Kind == STEXT {
+		if symbol.Func == nil {
+			return fmt.Errorf("STEXT symbol without func: %d", symbol.IDIndex)
+		}
+		w.writeSTEXTFields(symbol.Func)
+	}
+	return w.writer.err
+}
+
+func (w *writer) writeSTEXTFields(fields *StextFields) {
+	w.writer.writeVarint(fields.Args)
+	w.writer.writeVarint(fields.Frame)
+	if fields.NoSplit {
+		w.writer.writeVarint(1)
+	} else {
+		w.writer.writeVarint(0)
+	}
+
+	var flags int64
+	if fields.Leaf {
+		flags |= 0x1
+	}
+	if fields.CFunc {
+		flags |= 0x1 << 1
+	}
+	if fields.TypeMethod {
+		flags |= 0x1 << 2
+	}
+	if fields.SharedFunc {
+		flags |= 0x1 << 3
+	}
+	w.writer.writeVarint(flags)
+
+	w.writer.writeVarint(int64(len(fields.Local)))
+	for _, local := range fields.Local {
+		w.writer.writeVarint(local.AsymIndex)
+		w.writer.writeVarint(local.Offset)
+		w.writer.writeVarint(local.Type)
+		w.writer.writeVarint(local.GotypeIndex)
+	}
+
+	w.writeDataAddr(fields.PCSP)
+	w.writeDataAddr(fields.PCFile)
+	w.writeDataAddr(fields.PCLine)
+	w.writeDataAddr(fields.PCInline)
+
+	w.writer.writeVarint(int64(len(fields.PCData)))
+	for _, pcdata := range fields.PCData {
+		w.writeDataAddr(pcdata)
+	}
+
+	w.writer.writeVarint(int64(len(fields.FuncDataIndex)))
+	for _, index := range fields.FuncDataIndex {
+		w.writer.writeVarint(index)
+	}
+	for _, offset := range fields.FuncDataOffset {
+		w.writer.writeVarint(offset)
+	}
+
+	w.writer.writeVarint(int64(len(fields.FileIndex)))
+	for _, index := range fields.FileIndex {
+		w.writer.writeVarint(index)
+	}
+
+	w.writer.writeVarint(int64(len(fields.InlineTree)))
+	for _, call := range fields.InlineTree {
+		w.writer.writeVarint(call.Parent)
+		w.writer.writeVarint(call.FileIndex)
+		w.writer.writeVarint(call.Line)
+		w.writer.writeVarint(call.FuncIndex)
+	}
+}
+
+// writeDataAddr writes the size of the data and remembers the data to be written to the data block.
+func (w *writer) writeDataAddr(addr DataAddr) {
+	w.writer.writeVarint(addr.Size)
+	w.data = append(w.data, addr)
+}
+
+// rawSymKind converts the SymKind to the symbol kind in the go19ld object file.
+func (h Header) rawSymKind(kind SymKind) (byte, error) {
+	table := h.symKindTable(FormatGo19)
+	if table == nil {
+		return byte(kind), nil
+	}
+
+	for i, k := range table {
+		if k == kind {
+			return byte(i), nil
+		}
+	}
+	return 0, fmt.Errorf("the kind %s is not supported by %s", kind, h.GoVersion)
+}
+
+// rawRelocType converts the RelocType to the relocation type in the go19ld object file.
+// The type which is not converted when the file is parsed is written as it is.
+func (h Header) rawRelocType(relocType RelocType) (int64, error) {
+	_, known := h.relocTypeTable(FormatGo19)
+	raw, unconverted := relocType.Unconverted()
+	switch {
+	case known && !unconverted && relocType <= R_ADDRCUOFF:
+		return int64(relocType), nil
+	case !known && unconverted:
+		return raw, nil
+	default:
+		return 0, fmt.Errorf("the relocation type %s is not supported by %s", relocType, h.GoVersion)
+	}
+}
+
+// writerWithCounter is bufio.Writer which records the number of written bytes.
+// Like readerWithCounter, it updates an error field rather than returning it.
+type writerWithCounter struct {
+	raw             *bufio.Writer
+	numWrittenBytes int64
+	err             error
+}
+
+func (w *writerWithCounter) writeVarint(v int64) {
+	buff := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buff, zigzagEncode(v))
+	w.write(buff[:n])
+}
+
+func (w *writerWithCounter) writeString(s string) {
+	w.writeVarint(int64(len(s)))
+	w.write([]byte(s))
+}
+
+func (w *writerWithCounter) writeByte(b byte) {
+	if w.err != nil {
+		return
+	}
+
+	w.err = w.raw.WriteByte(b)
+	if w.err == nil {
+		w.numWrittenBytes++
+	}
+}
+
+func (w *writerWithCounter) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+
+	var n int
+	n, w.err = w.raw.Write(p)
+	w.numWrittenBytes += int64(n)
+}
@@ -0,0 +1,111 @@
+package goobj
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFile_WriteTo(t *testing.T) {
+	expected, err := ioutil.ReadFile(helloworldObjPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	file, err := ParseBytes(expected)
+	if err != nil {
+		t.Fatalf("failed to parse file: %v", err)
+	}
+
+	buff := &bytes.Buffer{}
+	n, err := file.WriteTo(buff)
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if n != int64(len(expected)) {
+		t.Errorf("the number of written bytes should be %d, but %d", len(expected), n)
+	}
+	if !bytes.Equal(expected, buff.Bytes()) {
+		t.Errorf("the written object file should be same as the original one")
+	}
+}
+
+func TestFile_WriteTo_Reparse(t *testing.T) {
+	file := parseFileForTesting(t, helloworldObjPath)
+	// drop the 1st symbol's data from the data block
+	file.Symbols = file.Symbols[1:]
+
+	buff := &bytes.Buffer{}
+	if _, err := file.WriteTo(buff); err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	written, err := ParseBytes(buff.Bytes())
+	if err != nil {
+		t.Fatalf("error should be nil, but %v", err)
+	}
+	if len(written.Symbols) != len(file.Symbols) {
+		t.Fatalf("the number of symbols should be %d, but %d", len(file.Symbols), len(written.Symbols))
+	}
+	for i, symbol := range written.Symbols {
+		expected := file.DataBlock[file.Symbols[i].DataAddr.Offset : file.Symbols[i].DataAddr.Offset+file.Symbols[i].DataAddr.Size]
+		actual := written.DataBlock[symbol.DataAddr.Offset : symbol.DataAddr.Offset+symbol.DataAddr.Size]
+		if !bytes.Equal(expected, actual) {
+			t.Errorf("[%d] the data should be same", i)
+		}
+	}
+}
+
+func TestFile_WriteTo_DataOutOfRange(t *testing.T) {
+	file := &File{SymbolReferences: []SymbolReference{{}}, Symbols: []Symbol{{Kind: SRODATA, DataAddr: DataAddr{Size: 1}}}}
+	file.Format = FormatGo19
+	buff := &bytes.Buffer{}
+	if n, err := file.WriteTo(buff); err == nil || n != 0 {
+		t.Errorf("error should not be nil: %d, %v", n, err)
+	}
+	if buff.Len() != 0 {
+		t.Errorf("nothing should be written, but %d bytes", buff.Len())
+	}
+}
+
+func TestFile_WriteTo_UnsupportedFormat(t *testing.T) {
+	file := parseFileForTesting(t, helloworldIndexedObjPath)
+	buff := &bytes.Buffer{}
+	if _, err := file.WriteTo(buff); err == nil {
+		t.Errorf("error should not be nil")
+	}
+	if buff.Len() != 0 {
+		t.Errorf("nothing should be written, but %d bytes", buff.Len())
+	}
+}
+
+func TestHeader_rawSymKind(t *testing.T) {
+	if kind, err := (Header{GoVersion: "go1.10"}).rawSymKind(SDWARFLOC); err != nil || kind != 10 {
+		t.Errorf("kind should be 10, but %d, %v", kind, err)
+	}
+	if kind, err := (Header{GoVersion: "go1.12"}).rawSymKind(SABIALIAS); err != nil || kind != 12 {
+		t.Errorf("kind should be 12, but %d, %v", kind, err)
+	}
+	if _, err := (Header{GoVersion: "go1.12"}).rawSymKind(STEXTFIPS); err == nil {
+		t.Errorf("error should not be nil")
+	}
+}
+
+func TestHeader_rawRelocType(t *testing.T) {
+	if relocType, err := (Header{GoVersion: "go1.10"}).rawRelocType(R_CONST); err != nil || relocType != 14 {
+		t.Errorf("type should be 14, but %d, %v", relocType, err)
+	}
+	if relocType, err := (Header{GoVersion: "go1.12"}).rawRelocType(relocTypeUnconverted | 15); err != nil || relocType != 15 {
+		t.Errorf("type should be 15, but %d, %v", relocType, err)
+	}
+	for i, testData := range []struct {
+		goVersion string
+		relocType RelocType
+	}{
+		{goVersion: "go1.10", relocType: R_LOONG64_CALL36},
+		{goVersion: "go1.10", relocType: relocTypeUnconverted | 15},
+		{goVersion: "go1.12", relocType: R_CONST},
+	} {
+		if _, err := (Header{GoVersion: testData.goVersion}).rawRelocType(testData.relocType); err == nil {
+			t.Errorf("[%d] error should not be nil", i)
+		}
+	}
+}